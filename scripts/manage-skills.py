@@ -9,10 +9,17 @@ Usage:
     python manage.py install    # Install skills and commands
     python manage.py uninstall  # Uninstall skills and commands
     python manage.py status     # Check installation status
+    python manage.py doctor     # Check the Go tools and gog
+
+Skills with a Go tool (scripts/go.mod) are also built into <claude-dir>/bin,
+get a default config.json if they have none, and are checked by doctor.
 """
 
 import argparse
+import json
 import os
+import shutil
+import subprocess
 import sys
 from pathlib import Path
 
@@ -38,10 +45,22 @@ if sys.platform == "win32" and not os.environ.get("WT_SESSION"):
         Colors.disable()
 
 
-def get_paths():
+# Written to <skill>/config.json on install when the skill has none.
+# week_start is left out on purpose: a config value overrides
+# BRIEF_WEEK_START, which both skills share.
+DEFAULT_CONFIGS = {
+    "calendar-brief": {
+        "working_hours": {"start": "09:00", "end": "18:00"},
+        "min_gap_minutes": 30,
+    },
+}
+
+
+def get_paths(claude_dir: Path = None):
     """Get source and destination paths."""
     repo_dir = Path(__file__).resolve().parent.parent
-    claude_dir = Path.home() / ".claude"
+    if claude_dir is None:
+        claude_dir = Path.home() / ".claude"
 
     return {
         "repo": repo_dir,
@@ -49,6 +68,7 @@ def get_paths():
         "skills_dest": claude_dir / "skills",
         "commands_src": repo_dir / "commands",
         "commands_dest": claude_dir / "commands",
+        "bin_dest": claude_dir / "bin",
     }
 
 
@@ -67,6 +87,14 @@ def is_valid_skill(skill_path: Path) -> bool:
     return (skill_path / "SKILL.md").exists()
 
 
+def go_tools(paths: dict) -> list[Path]:
+    """Skill directories that ship a Go tool in scripts/."""
+    return [
+        d for d in sorted(paths["skills_src"].iterdir())
+        if d.is_dir() and is_valid_skill(d) and (d / "scripts" / "go.mod").exists()
+    ]
+
+
 def is_our_symlink(link_path: Path, target_path: Path) -> bool:
     """Check if symlink points to our target."""
     if not link_path.is_symlink():
@@ -312,6 +340,128 @@ def uninstall_commands(paths: dict) -> tuple[int, int]:
     return removed, skipped
 
 
+def build_tools(paths: dict) -> tuple[int, int]:
+    """Build each Go tool into bin_dest. Returns (built, failed) counts."""
+    built = 0
+    failed = 0
+
+    print_section("Building Tools...")
+    print(f"  Target: {paths['bin_dest']}\n")
+
+    if not shutil.which("go"):
+        print(f"  {Colors.RED}✗  go not found on PATH; install Go 1.21+ and rerun{Colors.RESET}")
+        return 0, len(go_tools(paths))
+
+    paths["bin_dest"].mkdir(parents=True, exist_ok=True)
+    for skill_dir in go_tools(paths):
+        binary = paths["bin_dest"] / skill_dir.name
+        result = subprocess.run(
+            ["go", "build", "-o", str(binary), "."],
+            cwd=skill_dir / "scripts", capture_output=True, text=True,
+        )
+        if result.returncode == 0:
+            print(f"  {Colors.GREEN}✓  {skill_dir.name}{Colors.RESET}")
+            built += 1
+        else:
+            print(f"  {Colors.RED}✗  {skill_dir.name}{Colors.RESET}")
+            for line in result.stderr.strip().splitlines():
+                print(f"     {line}")
+            failed += 1
+
+    return built, failed
+
+
+def remove_tools(paths: dict) -> int:
+    """Remove the binaries build_tools wrote. Returns the count removed."""
+    removed = 0
+
+    print_section("Removing Tools...")
+    print(f"  Target: {paths['bin_dest']}\n")
+    for skill_dir in go_tools(paths):
+        binary = paths["bin_dest"] / skill_dir.name
+        if binary.is_file():
+            binary.unlink()
+            print(f"  {Colors.GREEN}✓  Removed {skill_dir.name}{Colors.RESET}")
+            removed += 1
+        else:
+            print(f"  ○  {skill_dir.name} (not built)")
+    return removed
+
+
+def write_default_configs(paths: dict) -> int:
+    """Create config.json for Go tools that have none. Returns the count written."""
+    written = 0
+
+    print_section("Writing Default Configs...")
+    for skill_dir in go_tools(paths):
+        defaults = DEFAULT_CONFIGS.get(skill_dir.name)
+        config = skill_dir / "config.json"
+        if defaults is None:
+            continue
+        if config.exists():
+            print(f"  {Colors.GREEN}✓  {skill_dir.name} (keeping existing config.json){Colors.RESET}")
+            continue
+        config.write_text(json.dumps(defaults, indent=2) + "\n")
+        print(f"  {Colors.GREEN}✓  {skill_dir.name}/config.json{Colors.RESET}")
+        written += 1
+
+    return written
+
+
+def run_doctor(paths: dict) -> int:
+    """Check that the Go tools can run. Returns the number of problems."""
+    problems = 0
+
+    def check(ok: bool, label: str, hint: str = ""):
+        nonlocal problems
+        if ok:
+            print(f"  {Colors.GREEN}✓  {label}{Colors.RESET}")
+        else:
+            print(f"  {Colors.RED}✗  {label}{Colors.RESET}")
+            if hint:
+                print(f"     {hint}")
+            problems += 1
+
+    print_section("Doctor")
+
+    check(shutil.which("go") is not None, "go on PATH", "Install Go 1.21+ from https://go.dev/dl/")
+    gog = shutil.which("gog")
+    check(gog is not None, "gog on PATH", "Install gog and run `gog auth add <email>`")
+    if gog:
+        accounts = []
+        try:
+            result = subprocess.run(["gog", "auth", "list", "--json"], capture_output=True, text=True, timeout=10)
+            accounts = json.loads(result.stdout).get("accounts", []) if result.returncode == 0 else []
+        except (subprocess.TimeoutExpired, ValueError):
+            pass
+        check(len(accounts) > 0, f"gog accounts ({len(accounts)} authorized)", "Run `gog auth add <email>`")
+
+    for skill_dir in go_tools(paths):
+        name = skill_dir.name
+        config = skill_dir / "config.json"
+        if config.exists():
+            try:
+                json.loads(config.read_text())
+                check(True, f"{name}/config.json is valid JSON")
+            except ValueError as e:
+                check(False, f"{name}/config.json is valid JSON", str(e))
+
+        # SKILL.md runs the binary, so a skill without one cannot work.
+        binary = paths["bin_dest"] / name
+        if not binary.exists():
+            check(False, f"{name} binary", f"Missing {binary}, which {name}/SKILL.md runs; run install")
+            continue
+        # --pending only reads the local state directory, so it needs no network.
+        try:
+            result = subprocess.run([str(binary), "--pending"], capture_output=True, text=True, timeout=10)
+            json.loads(result.stdout)
+            check(result.returncode == 0, f"{name} binary runs", result.stderr.strip())
+        except (subprocess.TimeoutExpired, ValueError, OSError) as e:
+            check(False, f"{name} binary runs", str(e))
+
+    return problems
+
+
 def check_status(paths: dict):
     """Check and display installation status."""
     skills_src = paths["skills_src"]
@@ -368,37 +518,54 @@ def check_status(paths: dict):
 
 def cmd_install(args):
     """Handle install command."""
-    paths = get_paths()
+    paths = get_paths(args.claude_dir)
     print_header("Installing Skills and Commands")
 
     skills_installed, skills_skipped = install_skills(paths, not args.yes)
     commands_installed, commands_skipped = install_commands(paths, not args.yes)
+    tools_built, tools_failed = build_tools(paths)
+    write_default_configs(paths)
+    problems = run_doctor(paths)
 
     print_header("Installation Complete!")
     print(f"  Skills:   Installed: {skills_installed} | Skipped: {skills_skipped}")
     print(f"  Commands: Installed: {commands_installed} | Skipped: {commands_skipped}")
+    print(f"  Tools:    Built: {tools_built} | Failed: {tools_failed}")
+    print(f"  Doctor:   Problems: {problems}")
+    if tools_failed or problems:
+        sys.exit(1)
 
 
 def cmd_uninstall(args):
     """Handle uninstall command."""
-    paths = get_paths()
+    paths = get_paths(args.claude_dir)
     print_header("Uninstalling Skills and Commands")
 
     skills_removed, skills_skipped = uninstall_skills(paths)
     commands_removed, commands_skipped = uninstall_commands(paths)
+    tools_removed = remove_tools(paths)
 
     print_header("Uninstallation Complete!")
     print(f"  Skills:   Removed: {skills_removed} | Skipped: {skills_skipped}")
     print(f"  Commands: Removed: {commands_removed} | Skipped: {commands_skipped}")
+    print(f"  Tools:    Removed: {tools_removed}")
 
 
 def cmd_status(args):
     """Handle status command."""
-    paths = get_paths()
+    paths = get_paths(args.claude_dir)
     print_header("Installation Status")
     check_status(paths)
 
 
+def cmd_doctor(args):
+    """Handle doctor command."""
+    paths = get_paths(args.claude_dir)
+    print_header("Checking Tools")
+    if run_doctor(paths):
+        sys.exit(1)
+
+
 def main():
     parser = argparse.ArgumentParser(
         description="Claude Code Skills & Commands Manager",
@@ -409,8 +576,14 @@ Examples:
   python manage.py install -y   Install without prompts
   python manage.py uninstall    Uninstall all skills and commands
   python manage.py status       Check installation status
+  python manage.py doctor       Check the Go tools, gog and configs
+  python manage.py --claude-dir /tmp/claude install -y
         """,
     )
+    parser.add_argument(
+        "--claude-dir", type=Path, default=Path.home() / ".claude",
+        help="Claude Code directory to install into (default: ~/.claude)",
+    )
 
     subparsers = parser.add_subparsers(dest="command", help="Available commands")
 
@@ -431,6 +604,10 @@ Examples:
     status_parser = subparsers.add_parser("status", help="Check installation status")
     status_parser.set_defaults(func=cmd_status)
 
+    # Doctor command
+    doctor_parser = subparsers.add_parser("doctor", help="Check the Go tools, gog and configs")
+    doctor_parser.set_defaults(func=cmd_doctor)
+
     args = parser.parse_args()
 
     if not args.command:
@@ -0,0 +1,20 @@
+package main
+
+// --- Meetings Without an Agenda ---
+
+const defaultAgendaMinutes = 30
+
+// lacksAgenda reports whether a meeting with other people that runs longer
+// than minMinutes has neither a description nor an attached document.
+func lacksAgenda(event GogEvent, e SimplifiedEvent, minMinutes int) bool {
+	if e.AllDay || e.Status == "cancelled" || e.EventType != "default" || e.DurationMinutes <= minMinutes {
+		return false
+	}
+	if e.AttendeeCount < 2 {
+		return false // a personal block, not a meeting
+	}
+	if plainDescription(event.Description, len(event.Description)) != "" {
+		return false
+	}
+	return len(event.Attachments) == 0
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// --- Keyword Alerts ---
+
+// AlertRule fires when an event whose summary or location matches Match (a
+// case-insensitive regex) changes in one of the On ways: added, moved or
+// removed (default added and moved).
+type AlertRule struct {
+	Name  string   `json:"name"`
+	Match string   `json:"match"`
+	On    []string `json:"on,omitempty"`
+}
+
+type Alert struct {
+	Rule          string `json:"rule"`
+	Change        string `json:"change"` // added, moved or removed
+	Summary       string `json:"summary"`
+	Start         string `json:"start"`
+	PreviousStart string `json:"previous_start,omitempty"`
+	EventID       string `json:"event_id,omitempty"`
+}
+
+// snapshotEvent is what the previous run saw of an event. End, Status and
+// Account are only kept by --diff.
+type snapshotEvent struct {
+	Summary  string `json:"summary"`
+	Location string `json:"location,omitempty"`
+	Start    string `json:"start"`
+	End      string `json:"end,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Account  string `json:"account,omitempty"`
+}
+
+type compiledRule struct {
+	AlertRule
+	pattern *regexp.Regexp
+	on      map[string]bool
+}
+
+func compileAlertRules(rules []AlertRule) ([]compiledRule, error) {
+	compiled := make([]compiledRule, 0, len(rules))
+	for _, r := range rules {
+		pattern, err := regexp.Compile("(?i)" + r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %q: %v", r.Name, err)
+		}
+		on := map[string]bool{}
+		for _, c := range r.On {
+			on[c] = true
+		}
+		if len(on) == 0 {
+			on["added"], on["moved"] = true, true
+		}
+		compiled = append(compiled, compiledRule{r, pattern, on})
+	}
+	return compiled, nil
+}
+
+// checkAlerts compares events with the snapshot the previous run saved for
+// the same date range and returns the changes that match a rule. The first
+// run for a range only saves the snapshot, otherwise every event would look
+// added.
+func checkAlerts(events []SimplifiedEvent, rules []compiledRule, from, to time.Time) []Alert {
+	rangeKey := from.Format(time.RFC3339) + "/" + to.Format(time.RFC3339)
+	snapshots := map[string]map[string]snapshotEvent{}
+	loadState("alerts-snapshot.json", &snapshots)
+	previous, seeded := snapshots[rangeKey]
+
+	current := map[string]snapshotEvent{}
+	for _, e := range events {
+		if e.EventID != "" {
+			current[e.EventID] = snapshotEvent{Summary: e.Summary, Location: e.Location, Start: e.Start}
+		}
+	}
+	snapshots[rangeKey] = current
+	saveState("alerts-snapshot.json", snapshots)
+	if !seeded {
+		return nil
+	}
+
+	alerts := []Alert{}
+	fire := func(change, id string, now snapshotEvent, before string) {
+		for _, r := range rules {
+			if r.on[change] && (r.pattern.MatchString(now.Summary) || r.pattern.MatchString(now.Location)) {
+				alerts = append(alerts, Alert{Rule: r.Name, Change: change, Summary: now.Summary, Start: now.Start, PreviousStart: before, EventID: id})
+			}
+		}
+	}
+	for _, e := range events {
+		now, ok := current[e.EventID]
+		if !ok {
+			continue
+		}
+		before, existed := previous[e.EventID]
+		switch {
+		case !existed:
+			fire("added", e.EventID, now, "")
+		case !sameInstant(before.Start, now.Start):
+			fire("moved", e.EventID, now, before.Start)
+		}
+	}
+	var removed []string
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Strings(removed)
+	for _, id := range removed {
+		fire("removed", id, previous[id], "")
+	}
+	return alerts
+}
+
+// sameInstant compares two start values that may be in different offsets.
+func sameInstant(a, b string) bool {
+	ta, errA := time.Parse(time.RFC3339, a)
+	tb, errB := time.Parse(time.RFC3339, b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return ta.Equal(tb)
+}
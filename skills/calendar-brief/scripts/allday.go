@@ -0,0 +1,43 @@
+package main
+
+import "time"
+
+// --- All-day Events ---
+
+// expandAllDay replaces each multi-day all-day event with one entry per day
+// inside [from, to), so a week view shows it on every day it covers.
+func expandAllDay(events []SimplifiedEvent, from, to time.Time) []SimplifiedEvent {
+	first, last := from.Format("2006-01-02"), to.Format("2006-01-02")
+	expanded := make([]SimplifiedEvent, 0, len(events))
+	for _, e := range events {
+		dates := allDayDates(e, from.Location())
+		if !e.AllDay || len(dates) <= 1 {
+			expanded = append(expanded, e)
+			continue
+		}
+		for _, d := range dates {
+			if d < first || d >= last {
+				continue
+			}
+			day := e
+			day.Start = d
+			next, _ := time.Parse("2006-01-02", d)
+			day.End = next.AddDate(0, 0, 1).Format("2006-01-02")
+			expanded = append(expanded, day)
+		}
+	}
+	return expanded
+}
+
+// splitAllDay separates all-day events from timed ones.
+func splitAllDay(events []SimplifiedEvent) (timed, allDay []SimplifiedEvent) {
+	timed, allDay = []SimplifiedEvent{}, []SimplifiedEvent{}
+	for _, e := range events {
+		if e.AllDay {
+			allDay = append(allDay, e)
+		} else {
+			timed = append(timed, e)
+		}
+	}
+	return timed, allDay
+}
@@ -0,0 +1,75 @@
+package main
+
+// --- Attendees ---
+
+type Attendee struct {
+	Name     string `json:"name,omitempty"`
+	Email    string `json:"email"`
+	Response string `json:"response"`
+	Optional bool   `json:"optional,omitempty"`
+	Self     bool   `json:"self,omitempty"`
+
+	// OutOfOffice is set when the attendee declined because they are away,
+	// which Google Calendar does automatically from their OOO block.
+	OutOfOffice bool `json:"out_of_office,omitempty"`
+}
+
+// TeammateOOO is an attendee who is away for one of my meetings.
+type TeammateOOO struct {
+	Name    string `json:"name,omitempty"`
+	Email   string `json:"email"`
+	Summary string `json:"summary"` // the meeting they will miss
+	Start   string `json:"start"`
+}
+
+// extractAttendees lists the people invited to an event. Rooms and other
+// resources are left out; they are not people to prepare for.
+func extractAttendees(event GogEvent) []Attendee {
+	if event.Attendees == nil {
+		return nil
+	}
+	attendees := make([]Attendee, 0, len(event.Attendees))
+	for _, a := range event.Attendees {
+		if a.Resource {
+			continue
+		}
+		attendees = append(attendees, Attendee{
+			Name:        a.DisplayName,
+			Email:       a.Email,
+			Response:    a.ResponseStatus,
+			Optional:    a.Optional,
+			Self:        a.Self,
+			OutOfOffice: a.ResponseStatus == "declined" && outOfOfficePattern.MatchString(a.Comment),
+		})
+	}
+	return attendees
+}
+
+// findTeammatesOOO lists the other attendees of e who are out of office.
+func findTeammatesOOO(e SimplifiedEvent) []TeammateOOO {
+	var away []TeammateOOO
+	for _, a := range e.Attendees {
+		if a.OutOfOffice && !a.Self {
+			away = append(away, TeammateOOO{Name: a.Name, Email: a.Email, Summary: e.Summary, Start: e.Start})
+		}
+	}
+	return away
+}
+
+// smallMeetingMax is the most people a meeting can have and still be small;
+// beyond it a meeting is large, more broadcast than discussion.
+const smallMeetingMax = 8
+
+// meetingSize classifies an event by the people invited, me included:
+// one_on_one, small or large. Events without attendees have no size.
+func meetingSize(attendeeCount int) string {
+	switch {
+	case attendeeCount < 2:
+		return ""
+	case attendeeCount == 2:
+		return "one_on_one"
+	case attendeeCount <= smallMeetingMax:
+		return "small"
+	}
+	return "large"
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Back-to-back Meetings ---
+
+// markBackToBack sets BackToBack on every meeting that starts exactly when
+// another ends, or ends exactly when another starts, and returns the length in
+// minutes of the longest streak: a run of two or more meetings with no gap
+// between one and the next. Overlapping meetings continue a streak (they are
+// reported as conflicts separately).
+func markBackToBack(events []SimplifiedEvent) int {
+	type meeting struct {
+		index      int
+		start, end time.Time
+	}
+	var meetings []meeting
+	starts, ends := map[int64]bool{}, map[int64]bool{}
+	for i, e := range events {
+		start, end, ok := blocksTime(e)
+		if !ok {
+			continue
+		}
+		meetings = append(meetings, meeting{i, start, end})
+		starts[start.Unix()] = true
+		ends[end.Unix()] = true
+	}
+	for _, m := range meetings {
+		if ends[m.start.Unix()] || starts[m.end.Unix()] {
+			events[m.index].BackToBack = true
+		}
+	}
+
+	sort.Slice(meetings, func(i, j int) bool { return meetings[i].start.Before(meetings[j].start) })
+	longest := 0
+	for i := 0; i < len(meetings); {
+		runStart, runEnd, count := meetings[i].start, meetings[i].end, 1
+		j := i + 1
+		for ; j < len(meetings) && !meetings[j].start.After(runEnd); j++ {
+			if meetings[j].end.After(runEnd) {
+				runEnd = meetings[j].end
+			}
+			count++
+		}
+		if minutes := int(runEnd.Sub(runStart).Minutes()); count > 1 && minutes > longest {
+			longest = minutes
+		}
+		i = j
+	}
+	return longest
+}
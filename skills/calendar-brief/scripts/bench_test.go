@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// --- Benchmarks ---
+
+// The benchmarks run the parsing and merge paths of a brief over two
+// synthetic calendars that share some meetings, e.g.
+//
+//	go test -run '^$' -bench . -benchmem
+const benchEvents = 2000 // per account, about a busy year
+
+// syntheticCalendar returns gog's JSON for n half-hour and hour-long events,
+// several a day, every fourth one also on the other account's calendar.
+func syntheticCalendar(n int, self string) []byte {
+	base := time.Date(2026, 1, 5, 9, 0, 0, 0, time.UTC)
+	events := make([]map[string]interface{}, n)
+	for i := range events {
+		start := base.AddDate(0, 0, i/8).Add(time.Duration(i%8) * time.Hour)
+		if i%5 == 0 {
+			start = start.Add(30 * time.Minute) // overlaps the next one
+		}
+		end := start.Add(time.Duration(30+30*(i%2)) * time.Minute)
+		uid := fmt.Sprintf("%s-%d@example.com", self, i)
+		if i%4 == 0 {
+			uid = fmt.Sprintf("shared-%d@example.com", i)
+		}
+		events[i] = map[string]interface{}{
+			"id":          fmt.Sprintf("%s-%d", self, i),
+			"iCalUID":     uid,
+			"summary":     fmt.Sprintf("Sync #%d", i),
+			"status":      "confirmed",
+			"start":       map[string]interface{}{"dateTime": start.Format(time.RFC3339)},
+			"end":         map[string]interface{}{"dateTime": end.Format(time.RFC3339)},
+			"hangoutLink": "https://meet.google.com/abc-defg-hij",
+			"organizer":   map[string]interface{}{"email": "lead@corp.example"},
+			"attendees": []interface{}{
+				map[string]interface{}{"email": self, "self": true, "responseStatus": "accepted"},
+				map[string]interface{}{"email": "lead@corp.example", "responseStatus": "accepted"},
+				map[string]interface{}{"email": "dave@partner.example", "responseStatus": "declined", "comment": "OOO"},
+			},
+		}
+	}
+	out, _ := json.Marshal(map[string]interface{}{"events": events})
+	return out
+}
+
+// benchMerged returns both accounts' events simplified, in the order the
+// brief merges them.
+func benchMerged(b *testing.B) []SimplifiedEvent {
+	b.Helper()
+	var merged []SimplifiedEvent
+	for _, self := range []string{"me@gmail.com", "me@corp.example"} {
+		list, err := decodeList[GogEvent](bytes.NewReader(syntheticCalendar(benchEvents, self)), "events")
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, e := range list.Items {
+			merged = append(merged, simplifyEvent(e, classifyAccount(self)))
+		}
+	}
+	return merged
+}
+
+func BenchmarkParseEvents(b *testing.B) {
+	data := syntheticCalendar(benchEvents, "me@corp.example")
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeList[GogEvent](bytes.NewReader(data), "events"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSimplifyEvents(b *testing.B) {
+	list, err := decodeList[GogEvent](bytes.NewReader(syntheticCalendar(benchEvents, "me@corp.example")), "events")
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range list.Items {
+			simplifyEvent(e, "work")
+		}
+	}
+}
+
+func BenchmarkDedupeEvents(b *testing.B) {
+	merged := benchMerged(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dedupeEvents(append([]SimplifiedEvent(nil), merged...))
+	}
+}
+
+func BenchmarkDetectConflicts(b *testing.B) {
+	events := dedupeEvents(benchMerged(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		detectConflicts(append([]SimplifiedEvent(nil), events...))
+	}
+}
+
+func BenchmarkMarkBackToBack(b *testing.B) {
+	events := dedupeEvents(benchMerged(b))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		markBackToBack(append([]SimplifiedEvent(nil), events...))
+	}
+}
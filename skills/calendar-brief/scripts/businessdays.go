@@ -0,0 +1,38 @@
+package main
+
+import "time"
+
+// --- Business Days ---
+
+// isBusinessDay reports whether d is a weekday that is not in holidays
+// (keyed YYYY-MM-DD).
+func isBusinessDay(d time.Time, holidays map[string]bool) bool {
+	if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday {
+		return false
+	}
+	return !holidays[d.Format("2006-01-02")]
+}
+
+// addBusinessDays moves n business days forward from d (backward when n is
+// negative). d itself never counts.
+func addBusinessDays(d time.Time, n int, holidays map[string]bool) time.Time {
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		d = d.AddDate(0, 0, step)
+		if isBusinessDay(d, holidays) {
+			n--
+		}
+	}
+	return d
+}
+
+func holidaySet(dates []string) map[string]bool {
+	set := make(map[string]bool, len(dates))
+	for _, d := range dates {
+		set[d] = true
+	}
+	return set
+}
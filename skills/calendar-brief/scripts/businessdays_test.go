@@ -0,0 +1,35 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAddBusinessDays(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	holidays := holidaySet([]string{"2026-10-09", "2026-10-19"})
+	cases := []struct {
+		from string
+		n    int
+		want string
+	}{
+		{"2026-10-15", 1, "2026-10-16"}, // Thursday to Friday
+		{"2026-10-16", 1, "2026-10-20"}, // over the weekend and Monday's holiday
+		{"2026-10-17", 1, "2026-10-20"}, // from a Saturday
+		{"2026-10-14", 5, "2026-10-22"},
+		{"2026-10-20", -1, "2026-10-16"},
+		{"2026-10-13", -2, "2026-10-08"}, // back over the weekend and Friday's holiday
+		{"2026-10-17", 0, "2026-10-17"},
+	}
+	for _, c := range cases {
+		if got := addBusinessDays(day(c.from), c.n, holidays).Format("2006-01-02"); got != c.want {
+			t.Errorf("%s %+d: got %s, want %s", c.from, c.n, got, c.want)
+		}
+	}
+	if isBusinessDay(day("2026-10-19"), holidays) || !isBusinessDay(day("2026-10-19"), nil) {
+		t.Error("holidays are not business days, other weekdays are")
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// --- Clock ---
+
+// nowEnv pins the time a brief is computed for (RFC 3339), so a run can be
+// replayed against a fixed day. The contract tests set it to match their
+// fixtures.
+const nowEnv = "BRIEF_NOW"
+
+// currentTime is $BRIEF_NOW when set, and the wall clock otherwise.
+func currentTime() (time.Time, error) {
+	v := os.Getenv(nowEnv)
+	if v == "" {
+		return time.Now(), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s %q (want RFC 3339, e.g. 2026-10-17T09:00:00+09:00)", nowEnv, v)
+	}
+	return t, nil
+}
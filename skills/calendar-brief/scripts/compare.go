@@ -0,0 +1,70 @@
+package main
+
+import "time"
+
+// --- Period Comparison ---
+
+// --compare sets the week's stats beside the week before, so a weekly
+// review can say "3h more meetings, 2 fewer 1:1s" instead of repeating
+// absolute numbers.
+
+// StatsSummary holds the stats that are compared between periods.
+type StatsSummary struct {
+	MeetingCount         int     `json:"meeting_count"`
+	MeetingHours         float64 `json:"meeting_hours"`
+	OneOnOnes            int     `json:"one_on_ones"`
+	BookedPercent        float64 `json:"booked_percent"`
+	LongestStreakMinutes int     `json:"longest_streak_minutes"`
+	OutsideHoursMinutes  int     `json:"outside_hours_minutes"`
+	NoAgendaMeetings     int     `json:"no_agenda_meetings"`
+}
+
+type StatsComparison struct {
+	PreviousFrom string       `json:"previous_from"`
+	PreviousTo   string       `json:"previous_to"` // exclusive
+	Previous     StatsSummary `json:"previous"`
+	Delta        StatsSummary `json:"delta"` // this period minus the previous one
+}
+
+func summarizeStats(s WeekStats) StatsSummary {
+	return StatsSummary{
+		MeetingCount:         s.MeetingCount,
+		MeetingHours:         s.MeetingHours,
+		OneOnOnes:            s.OneOnOnes,
+		BookedPercent:        s.BookedPercent,
+		LongestStreakMinutes: s.LongestStreakMinutes,
+		OutsideHoursMinutes:  s.OutsideHoursMinutes,
+		NoAgendaMeetings:     s.NoAgendaMeetings,
+	}
+}
+
+// periodStats computes the stats of [from, to) from freshly fetched events,
+// the way the brief does for its own period.
+func periodStats(events []SimplifiedEvent, from, to time.Time, dayStart, dayEnd time.Duration, schedules map[string]schedule, loc *time.Location) WeekStats {
+	events = dedupeEvents(events)
+	sortEvents(events, loc)
+	streak := markBackToBack(events)
+	outside := markOutsideHours(events, schedules, loc)
+	stats := computeWeekStats(events, from, to, dayStart, dayEnd)
+	stats.LongestStreakMinutes, stats.OutsideHoursMinutes = streak, outside
+	return stats
+}
+
+// compareStats reports current against previous, which covers [from, to).
+func compareStats(current, previous WeekStats, from, to time.Time) *StatsComparison {
+	cur, prev := summarizeStats(current), summarizeStats(previous)
+	return &StatsComparison{
+		PreviousFrom: from.Format("2006-01-02"),
+		PreviousTo:   to.Format("2006-01-02"),
+		Previous:     prev,
+		Delta: StatsSummary{
+			MeetingCount:         cur.MeetingCount - prev.MeetingCount,
+			MeetingHours:         round1(cur.MeetingHours - prev.MeetingHours),
+			OneOnOnes:            cur.OneOnOnes - prev.OneOnOnes,
+			BookedPercent:        round1(cur.BookedPercent - prev.BookedPercent),
+			LongestStreakMinutes: cur.LongestStreakMinutes - prev.LongestStreakMinutes,
+			OutsideHoursMinutes:  cur.OutsideHoursMinutes - prev.OutsideHoursMinutes,
+			NoAgendaMeetings:     cur.NoAgendaMeetings - prev.NoAgendaMeetings,
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// --- Config ---
+
+// Config holds defaults read from ~/.claude/skills/calendar-brief/config.json.
+// Command-line flags always take precedence over config values.
+type Config struct {
+	Timezone     string       `json:"timezone"`
+	WorkingHours WorkingHours `json:"working_hours"`
+	MinGap       int          `json:"min_gap_minutes"`
+
+	// WeekStart is "mon" (the default) or "sun"; see resolveWeekStart.
+	WeekStart string `json:"week_start"`
+
+	// Locale is the default --locale ("en" or "ko") for --template and
+	// --format speech. The JSON output stays English either way.
+	Locale string `json:"locale"`
+
+	// AccountHours overrides WorkingHours per account, keyed by email or by
+	// account type ("work", "personal").
+	AccountHours map[string]WorkingHours `json:"account_working_hours"`
+
+	// Holidays are extra days off (YYYY-MM-DD); HolidayCalendar is a calendar
+	// ID such as "ko.south_korea#holiday@group.v.calendar.google.com".
+	Holidays        []string `json:"holidays"`
+	HolidayCalendar string   `json:"holiday_calendar"`
+
+	// TravelBufferMinutes is the time needed between meetings at different
+	// places; TravelCommand, when set, estimates it per pair instead (see
+	// newTravelEstimator).
+	TravelBufferMinutes int    `json:"travel_buffer_minutes"`
+	TravelCommand       string `json:"travel_command"`
+
+	// HourlyRate prices the person-hours reported by --cost.
+	HourlyRate float64 `json:"hourly_rate"`
+
+	// AlertRules are evaluated by --alerts.
+	AlertRules []AlertRule `json:"alert_rules"`
+
+	// ClassifyCommand, when set, decides the type of discovered accounts
+	// (see classifyAccounts).
+	ClassifyCommand string `json:"classify_command"`
+}
+
+type WorkingHours struct {
+	Start string   `json:"start"`
+	End   string   `json:"end"`
+	Days  []string `json:"days,omitempty"` // e.g. ["mon", "tue"]; default Monday-Friday
+}
+
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude", "skills", "calendar-brief", "config.json")
+}
+
+// loadConfig reads the config file at path. A missing file is not an error;
+// it just yields the zero Config.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"time"
+)
+
+// --- Conflict Detection ---
+
+type EventRef struct {
+	Summary     string `json:"summary"`
+	Start       string `json:"start"`
+	End         string `json:"end"`
+	AccountType string `json:"account_type"`
+}
+
+type Conflict struct {
+	First          EventRef `json:"first"`
+	Second         EventRef `json:"second"`
+	OverlapMinutes int      `json:"overlap_minutes"`
+}
+
+func refOf(e SimplifiedEvent) EventRef {
+	return EventRef{Summary: e.Summary, Start: e.Start, End: e.End, AccountType: e.AccountType}
+}
+
+// blocksTime reports whether an event occupies its slot: timed, not
+// cancelled, and not declined. On-call shifts and working-location entries
+// are excluded because meetings during them are expected.
+func blocksTime(e SimplifiedEvent) (time.Time, time.Time, bool) {
+	if e.Status == "cancelled" || e.Response == "declined" || e.OnCall || e.EventType == "workingLocation" {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err1 := time.Parse(time.RFC3339, e.Start)
+	end, err2 := time.Parse(time.RFC3339, e.End)
+	if err1 != nil || err2 != nil || !end.After(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// detectConflicts returns every pair of overlapping events and sets
+// HasConflict on each event that is part of one.
+func detectConflicts(events []SimplifiedEvent) []Conflict {
+	conflicts := []Conflict{}
+	for i := range events {
+		aStart, aEnd, ok := blocksTime(events[i])
+		if !ok {
+			continue
+		}
+		for j := i + 1; j < len(events); j++ {
+			bStart, bEnd, ok := blocksTime(events[j])
+			if !ok {
+				continue
+			}
+			// The same meeting on two calendars is not a conflict.
+			if events[i].Summary == events[j].Summary && aStart.Equal(bStart) && aEnd.Equal(bEnd) {
+				continue
+			}
+			overlapStart, overlapEnd := aStart, aEnd
+			if bStart.After(overlapStart) {
+				overlapStart = bStart
+			}
+			if bEnd.Before(overlapEnd) {
+				overlapEnd = bEnd
+			}
+			if !overlapEnd.After(overlapStart) {
+				continue
+			}
+			events[i].HasConflict = true
+			events[j].HasConflict = true
+			conflicts = append(conflicts, Conflict{
+				First:          refOf(events[i]),
+				Second:         refOf(events[j]),
+				OverlapMinutes: int(overlapEnd.Sub(overlapStart).Minutes()),
+			})
+		}
+	}
+	return conflicts
+}
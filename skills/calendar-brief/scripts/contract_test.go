@@ -0,0 +1,472 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// --- Contract Tests ---
+
+// These tests run the real program against a fake gog serving testdata/gog
+// and hold its JSON output to testdata/output.schema.json, the contract the
+// skill prompt reads. The test binary plays both parts: linked as "gog" it
+// answers gog commands, and with runMainEnv set it runs main().
+
+const (
+	runMainEnv  = "CALENDAR_BRIEF_RUN_MAIN"
+	fixturesEnv = "FAKE_GOG_FIXTURES"
+	failEnv     = "FAKE_GOG_FAIL" // account whose gog calls fail
+
+	// fixtureNow is the morning of the fixtures' day, so relative ranges
+	// and --next keep finding them.
+	fixtureNow = "2026-10-17T08:00:00+09:00"
+)
+
+func TestMain(m *testing.M) {
+	if filepath.Base(os.Args[0]) == "gog" {
+		os.Exit(fakeGog(os.Args[1:]))
+	}
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeGog serves the gog commands calendar-brief uses from fixture files.
+func fakeGog(args []string) int {
+	dir := os.Getenv(fixturesEnv)
+	account := ""
+	for _, a := range args {
+		if v, ok := strings.CutPrefix(a, "--account="); ok {
+			account = v
+		}
+	}
+	if account != "" && account == os.Getenv(failEnv) {
+		fmt.Fprintf(os.Stderr, "fake gog: %s is unavailable\n", account)
+		return 2
+	}
+
+	serve := func(name, fallback string) int {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			data = []byte(fallback)
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		os.Stdout.Write(data)
+		return 0
+	}
+	switch {
+	case len(args) >= 2 && args[0] == "auth" && args[1] == "list":
+		return serve("auth.json", `{"accounts": []}`)
+	case len(args) >= 3 && args[0] == "calendar" && args[1] == "events":
+		calendarID := args[2]
+		if calendarID == "primary" {
+			calendarID = account
+		}
+		return serve("events-"+calendarID+".json", `{"events": []}`)
+	case len(args) >= 3 && args[0] == "gmail" && args[1] == "messages" && args[2] == "search":
+		return serve("messages-"+account+".json", `{"messages": []}`)
+	case len(args) >= 2 && args[0] == "tasks" && args[1] == "list":
+		return serve("tasks-"+account+".json", `{"tasks": []}`)
+	}
+	fmt.Fprintf(os.Stderr, "fake gog: unknown command %q\n", strings.Join(args, " "))
+	return 1
+}
+
+// runBrief runs calendar-brief with args in a fresh home directory, so no
+// state carries over between runs, and returns its stdout and exit code.
+func runBrief(t *testing.T, env []string, args ...string) ([]byte, int) {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := filepath.Abs(filepath.Join("testdata", "gog"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	if err := os.Symlink(exe, filepath.Join(bin, "gog")); err != nil {
+		t.Skipf("cannot link fake gog: %v", err)
+	}
+	home := t.TempDir()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(),
+		runMainEnv+"=1",
+		fixturesEnv+"="+fixtures,
+		"PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"HOME="+home,
+		"XDG_CACHE_HOME="+filepath.Join(home, ".cache"),
+		"BRIEF_READ_ONLY=",
+		nowEnv+"="+fixtureNow,
+		"TZ=Asia/Seoul",
+	)
+	cmd.Env = append(cmd.Env, env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	code := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if stderr.Len() > 0 {
+		t.Logf("stderr: %s", stderr.String())
+	}
+	return stdout.Bytes(), code
+}
+
+// --- JSON Schema Subset ---
+
+// schema understands the parts of JSON Schema the contract uses: type,
+// required, properties, items, enum and local $refs to definitions.
+type schema struct {
+	Ref         string             `json:"$ref"`
+	Type        string             `json:"type"`
+	Required    []string           `json:"required"`
+	Properties  map[string]*schema `json:"properties"`
+	Items       *schema            `json:"items"`
+	Enum        []interface{}      `json:"enum"`
+	Definitions map[string]*schema `json:"definitions"`
+}
+
+func loadSchema(t *testing.T) *schema {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "output.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("output.schema.json: %v", err)
+	}
+	return &s
+}
+
+// validate returns one message per violation of s by v at path.
+func (s *schema) validate(root *schema, v interface{}, path string) []string {
+	if s.Ref != "" {
+		def, ok := root.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown $ref %s", path, s.Ref)}
+		}
+		return def.validate(root, v, path)
+	}
+	var problems []string
+	if s.Type != "" && !hasType(v, s.Type) {
+		return []string{fmt.Sprintf("%s: want %s, got %T", path, s.Type, v)}
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if e == v {
+				found = true
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", path, v, s.Enum))
+		}
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing %q", path, name))
+			}
+		}
+		for name, prop := range s.Properties {
+			if field, ok := obj[name]; ok {
+				problems = append(problems, prop.validate(root, field, path+"."+name)...)
+			}
+		}
+	}
+	if arr, ok := v.([]interface{}); ok && s.Items != nil {
+		for i, item := range arr {
+			problems = append(problems, s.Items.validate(root, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return problems
+}
+
+func hasType(v interface{}, want string) bool {
+	switch want {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	}
+	return false
+}
+
+// checkContract decodes out and fails the test on every schema violation.
+func checkContract(t *testing.T, s *schema, out []byte) map[string]interface{} {
+	t.Helper()
+	var v map[string]interface{}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatalf("output is not a JSON object: %v\n%s", err, out)
+	}
+	for _, p := range s.validate(s, v, "$") {
+		t.Error(p)
+	}
+	return v
+}
+
+// --- Flag Combinations ---
+
+var dateModes = [][]string{
+	{"--today"},
+	{"--tomorrow"},
+	{"--this-week"},
+	{"--next-week"},
+	{"--next-week", "--week-start=sun"},
+	{"--weekend"},
+	{"--date=2026-10-20"},
+	{"--from=2026-10-01", "--to=2026-10-07"},
+	{"--next=3"},
+}
+
+var accountSetups = []struct {
+	name         string
+	args         []string
+	env          []string
+	wantAccounts int
+	wantErrors   bool
+}{
+	{name: "discovered", wantAccounts: 2},
+	{name: "personal", args: []string{"--personal=me@gmail.com"}, wantAccounts: 1},
+	{name: "work", args: []string{"--work=me@corp.example"}, wantAccounts: 1},
+	{name: "both", args: []string{"--personal=me@gmail.com", "--work=me@corp.example"}, wantAccounts: 2},
+	{name: "one-failing", env: []string{failEnv + "=me@corp.example"}, wantAccounts: 2, wantErrors: true},
+}
+
+func TestContractJSON(t *testing.T) {
+	s := loadSchema(t)
+	for _, setup := range accountSetups {
+		for _, mode := range dateModes {
+			setup, mode := setup, mode
+			t.Run(setup.name+"/"+strings.Join(mode, " "), func(t *testing.T) {
+				t.Parallel()
+				args := append(append([]string{"--retries=0"}, setup.args...), mode...)
+				out, code := runBrief(t, setup.env, args...)
+				if code != 0 {
+					t.Fatalf("exit %d: %s", code, out)
+				}
+				v := checkContract(t, s, out)
+				if accounts, _ := v["accounts"].([]interface{}); len(accounts) != setup.wantAccounts {
+					t.Errorf("got %d accounts, want %d", len(accounts), setup.wantAccounts)
+				}
+				if errs, _ := v["errors"].([]interface{}); (len(errs) > 0) != setup.wantErrors {
+					t.Errorf("errors = %v, want errors: %v", errs, setup.wantErrors)
+				}
+				if events, _ := v["events"].([]interface{}); len(events) == 0 {
+					t.Error("no events")
+				}
+			})
+		}
+	}
+}
+
+func TestContractJSONModifiers(t *testing.T) {
+	s := loadSchema(t)
+	for _, extra := range [][]string{
+		{"--split-all-day"},
+		{"--tz=Asia/Seoul"},
+		{"--hide-declined=false", "--hide-cancelled"},
+		{"--hide-focus-time", "--exclude=standup"},
+		{"--ids=false"},
+		{"--attendees", "--description"},
+		{"--person=lead@corp.example"},
+		{"--timeline"},
+		{"--suggest-declines"},
+		{"--redact", "--attendees"},
+		{"--hide-optional"},
+		{"--compare"},
+		{"--tasks"},
+	} {
+		extra := extra
+		t.Run(strings.Join(extra, " "), func(t *testing.T) {
+			t.Parallel()
+			out, code := runBrief(t, nil, append([]string{"--this-week"}, extra...)...)
+			if code != 0 {
+				t.Fatalf("exit %d: %s", code, out)
+			}
+			checkContract(t, s, out)
+		})
+	}
+}
+
+func TestContractFormats(t *testing.T) {
+	formats := []struct {
+		format, prefix string
+	}{
+		{"ics", "BEGIN:VCALENDAR\r\n"},
+		{"csv", "category,date,start,end,hours,summary,account\n"},
+		{"speech", "You have "},
+	}
+	for _, f := range formats {
+		for _, mode := range dateModes {
+			f, mode := f, mode
+			t.Run(f.format+"/"+strings.Join(mode, " "), func(t *testing.T) {
+				t.Parallel()
+				out, code := runBrief(t, nil, append([]string{"--format=" + f.format}, mode...)...)
+				if code != 0 {
+					t.Fatalf("exit %d: %s", code, out)
+				}
+				if !strings.HasPrefix(string(out), f.prefix) {
+					t.Errorf("output starts %q, want %q", firstLine(out), f.prefix)
+				}
+			})
+		}
+	}
+}
+
+// --- Behavior ---
+
+// decodeEvents runs calendar-brief and returns its events.
+func decodeEvents(t *testing.T, args ...string) []map[string]interface{} {
+	t.Helper()
+	out, code := runBrief(t, nil, args...)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, out)
+	}
+	var v struct {
+		Events []map[string]interface{} `json:"events"`
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatal(err)
+	}
+	return v.Events
+}
+
+func eventIDs(events []map[string]interface{}) string {
+	ids := make([]string, len(events))
+	for i, e := range events {
+		ids[i], _ = e["event_id"].(string)
+	}
+	return strings.Join(ids, ",")
+}
+
+// The filters drop exactly the events they name, and --next counts from
+// $BRIEF_NOW. The Design review seen by both accounts is always one event.
+func TestContractFilters(t *testing.T) {
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--today"}, "p1,w1,w4,p2,w3,p3"},
+		{[]string{"--today", "--hide-declined=false"}, "p1,w1,w4,p2,w3,w5,p3"},
+		{[]string{"--today", "--hide-optional"}, "p1,w1,w4,p2,p3"},
+		{[]string{"--today", "--hide-focus-time", "--exclude=standup"}, "p1,p2,w3,p3"},
+		{[]string{"--next=3"}, "w1,w4,p2"},
+		{[]string{"--next=1"}, "w1"},
+	} {
+		c := c
+		t.Run(strings.Join(c.args, " "), func(t *testing.T) {
+			t.Parallel()
+			if got := eventIDs(decodeEvents(t, c.args...)); got != c.want {
+				t.Errorf("events %s, want %s", got, c.want)
+			}
+		})
+	}
+}
+
+func TestContractDedupe(t *testing.T) {
+	for _, e := range decodeEvents(t, "--today") {
+		if e["summary"] != "Design review" {
+			continue
+		}
+		accounts, _ := e["accounts"].([]interface{})
+		if fmt.Sprint(accounts) != "[me@gmail.com me@corp.example]" {
+			t.Errorf("accounts = %v, want both accounts", accounts)
+		}
+	}
+}
+
+// --redact blanks the private Dinner and drops every attendee and
+// organizer email, leaving the other events' summaries alone.
+func TestContractRedact(t *testing.T) {
+	for _, e := range decodeEvents(t, "--today", "--redact", "--attendees") {
+		if email, _ := e["organizer_email"].(string); email != "" {
+			t.Errorf("%s: organizer_email %q survived --redact", e["event_id"], email)
+		}
+		attendees, _ := e["attendees"].([]interface{})
+		for _, a := range attendees {
+			if email, _ := a.(map[string]interface{})["email"].(string); email != "" {
+				t.Errorf("%s: attendee %q survived --redact", e["event_id"], email)
+			}
+		}
+		switch e["event_id"] {
+		case "p3":
+			if e["summary"] != redactedSummary || e["location"] != "" {
+				t.Errorf("private event shows %q at %q", e["summary"], e["location"])
+			}
+		case "w1":
+			if e["summary"] != "Team standup" {
+				t.Errorf("public event summary %q", e["summary"])
+			}
+		}
+	}
+}
+
+// Failures the prompt relies on seeing as a JSON error object.
+func TestContractErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--format=xml"},
+		{"--weeks=-1"},
+		{"--compare", "--today"},
+		{"--from=yesterday-ish"},
+		{"--date=2026-10-20", "--from=2026-10-19"},
+		{"--this-week", "--week-start=tue"},
+		{"--free-at=tomorrow"},
+		{"--create", "--title=Sync"},
+		{"--create", "--title=Sync", "--start=2026-10-20 10:00"},
+		{"--create", "--title=Sync", "--start=2026-10-20 10:00", "--account=me@crop.example"},
+		{"--match=("},
+		{"--chart=out.svg", "--today"},
+	} {
+		args := args
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			t.Parallel()
+			out, code := runBrief(t, nil, args...)
+			if code == 0 {
+				t.Fatalf("exit 0, want failure: %s", out)
+			}
+			var v struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(out, &v); err != nil || v.Error == "" {
+				t.Errorf("want {\"error\": ...}, got %s", out)
+			}
+		})
+	}
+}
+
+func firstLine(b []byte) string {
+	line, _, _ := strings.Cut(string(b), "\n")
+	return line
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Meeting Cost ---
+
+// A meeting costs its duration times the people attending it: everyone
+// invited who has not declined, and at least me. With an hourly_rate in the
+// config the person-hours are also priced.
+
+type MeetingCost struct {
+	PersonHours float64           `json:"person_hours"`
+	Cost        float64           `json:"cost,omitempty"` // person_hours × hourly_rate
+	Meetings    []MeetingCostItem `json:"meetings"`       // most expensive first
+}
+
+type MeetingCostItem struct {
+	Summary     string  `json:"summary"`
+	Start       string  `json:"start"`
+	Minutes     int     `json:"minutes"`
+	Attendees   int     `json:"attendees"`
+	PersonHours float64 `json:"person_hours"`
+	Cost        float64 `json:"cost,omitempty"`
+	Recurring   bool    `json:"recurring,omitempty"`
+}
+
+// computeMeetingCost prices the meetings that block time in [from, to).
+func computeMeetingCost(events []SimplifiedEvent, from, to time.Time, hourlyRate float64) *MeetingCost {
+	cost := &MeetingCost{Meetings: []MeetingCostItem{}}
+	var total float64
+	for _, e := range events {
+		start, end, ok := blocksTime(e)
+		if !ok || !start.Before(to) || !end.After(from) {
+			continue
+		}
+		people := max(e.attending, 1)
+		minutes := int(end.Sub(start).Minutes())
+		personHours := float64(people*minutes) / 60
+		total += personHours
+		cost.Meetings = append(cost.Meetings, MeetingCostItem{
+			Summary:     e.Summary,
+			Start:       e.Start,
+			Minutes:     minutes,
+			Attendees:   people,
+			PersonHours: round1(personHours),
+			Cost:        round1(personHours * hourlyRate),
+			Recurring:   e.recurring,
+		})
+	}
+	sort.SliceStable(cost.Meetings, func(i, j int) bool {
+		return cost.Meetings[i].PersonHours > cost.Meetings[j].PersonHours
+	})
+	cost.PersonHours = round1(total)
+	cost.Cost = round1(total * hourlyRate)
+	return cost
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// --- Event Creation ---
+
+type newEvent struct {
+	Summary   string
+	Start     time.Time
+	End       time.Time
+	Location  string
+	Attendees []string
+}
+
+type CreateOutput struct {
+	Account *Account         `json:"account,omitempty"`
+	Event   *SimplifiedEvent `json:"event,omitempty"`
+	Staged  *PendingAction   `json:"staged,omitempty"` // with --require-approval
+	Errors  []AccountError   `json:"errors,omitempty"`
+}
+
+// createEvent adds ev to the account's primary calendar and logs it for
+// --undo. It returns the event as gog printed it.
+func createEvent(account string, ev newEvent) (GogEvent, error) {
+	args := []string{
+		"calendar", "create", "primary",
+		fmt.Sprintf("--summary=%s", ev.Summary),
+		fmt.Sprintf("--from=%s", ev.Start.Format(time.RFC3339)),
+		fmt.Sprintf("--to=%s", ev.End.Format(time.RFC3339)),
+	}
+	if ev.Location != "" {
+		args = append(args, fmt.Sprintf("--location=%s", ev.Location))
+	}
+	if len(ev.Attendees) > 0 {
+		args = append(args, fmt.Sprintf("--attendees=%s", strings.Join(ev.Attendees, ",")))
+	}
+	args = append(args, "--json", fmt.Sprintf("--account=%s", account))
+
+	// gog prints the created event either bare or as {"event": {...}}.
+	var created struct {
+		GogEvent
+		Event *GogEvent `json:"event"`
+	}
+	err := streamGog(context.Background(), func(r io.Reader) error {
+		json.NewDecoder(r).Decode(&created) // older gog versions print less; runCreate fills in
+		return nil
+	}, args...)
+	if created.Event != nil {
+		created.GogEvent = *created.Event
+	}
+	params := map[string]string{
+		"summary":  ev.Summary,
+		"start":    ev.Start.Format(time.RFC3339),
+		"end":      ev.End.Format(time.RFC3339),
+		"event_id": created.ID,
+	}
+	if ev.Location != "" {
+		params["location"] = ev.Location
+	}
+	if len(ev.Attendees) > 0 {
+		params["attendees"] = strings.Join(ev.Attendees, ",")
+	}
+	recordAction("event_create", account, params, err)
+	return created.GogEvent, err
+}
+
+// parseEnd reads --end as a full date and time, or as HH:MM on start's day.
+func parseEnd(s string, start time.Time) (time.Time, error) {
+	if clock, err := parseClock(strings.TrimSpace(s)); err == nil {
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+		return day.Add(clock), nil
+	}
+	return parseDateTime(s, start.Location())
+}
+
+// eventFromFlags builds the --create event; times are read in loc.
+func eventFromFlags(title, start, end string, minutes int, location, invite string, loc *time.Location) (newEvent, error) {
+	if strings.TrimSpace(title) == "" || start == "" {
+		return newEvent{}, fmt.Errorf("--create needs --title and --start")
+	}
+	ev := newEvent{Summary: strings.TrimSpace(title), Location: strings.TrimSpace(location)}
+	var err error
+	if ev.Start, err = parseDateTime(start, loc); err != nil {
+		return newEvent{}, fmt.Errorf("--start: %v", err)
+	}
+	ev.End = ev.Start.Add(time.Duration(minutes) * time.Minute)
+	if end != "" {
+		if ev.End, err = parseEnd(end, ev.Start); err != nil {
+			return newEvent{}, fmt.Errorf("--end: %v", err)
+		}
+	}
+	if !ev.End.After(ev.Start) {
+		return newEvent{}, fmt.Errorf("--end %s is not after --start %s", ev.End.Format(time.RFC3339), ev.Start.Format(time.RFC3339))
+	}
+	for _, email := range strings.Split(invite, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			ev.Attendees = append(ev.Attendees, email)
+		}
+	}
+	return ev, nil
+}
+
+// pickAccount chooses the calendar to write to: email when given, else the
+// only account there is. email must be one of accounts, so a typo cannot
+// send gog to an account the brief does not know.
+func pickAccount(accounts []Account, email string) (Account, error) {
+	if email == "" && len(accounts) == 1 {
+		return accounts[0], nil
+	}
+	for _, a := range accounts {
+		if email != "" && strings.EqualFold(a.Email, email) {
+			return a, nil
+		}
+	}
+	var emails []string
+	for _, a := range accounts {
+		emails = append(emails, a.Email)
+	}
+	if email == "" {
+		return Account{}, fmt.Errorf("pick the calendar with --account (one of %s)", strings.Join(emails, ", "))
+	}
+	return Account{}, fmt.Errorf("unknown --account %s (want one of %s)", email, strings.Join(emails, ", "))
+}
+
+// runCreate creates ev on the account, or stages it for --approve.
+func runCreate(account Account, ev newEvent, stage bool) CreateOutput {
+	output := CreateOutput{Account: &account}
+	if stage {
+		p, err := stageAction("create", account.Email, map[string]string{
+			"summary":   ev.Summary,
+			"start":     ev.Start.Format(time.RFC3339),
+			"end":       ev.End.Format(time.RFC3339),
+			"location":  ev.Location,
+			"attendees": strings.Join(ev.Attendees, ","),
+		})
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			return output
+		}
+		output.Staged = &p
+		return output
+	}
+	created, err := createEvent(account.Email, ev)
+	if err != nil {
+		output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+		return output
+	}
+	// Older gog versions print little more than the new ID.
+	if created.Summary == "" {
+		created.Summary = ev.Summary
+	}
+	if created.Start.value() == "" {
+		created.Start = GogEventTime{DateTime: ev.Start.Format(time.RFC3339)}
+		created.End = GogEventTime{DateTime: ev.End.Format(time.RFC3339)}
+	}
+	if created.Location == "" {
+		created.Location = ev.Location
+	}
+	simplified := simplifyEvent(created, account.Type)
+	output.Event = &simplified
+	return output
+}
+
+// stagedEvent rebuilds the event of a staged "create" action.
+func stagedEvent(params map[string]string) (newEvent, error) {
+	start, err := time.Parse(time.RFC3339, params["start"])
+	if err != nil {
+		return newEvent{}, err
+	}
+	end, err := time.Parse(time.RFC3339, params["end"])
+	if err != nil {
+		return newEvent{}, err
+	}
+	ev := newEvent{Summary: params["summary"], Start: start, End: end, Location: params["location"]}
+	if params["attendees"] != "" {
+		ev.Attendees = strings.Split(params["attendees"], ",")
+	}
+	return ev, nil
+}
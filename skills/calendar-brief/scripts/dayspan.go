@@ -0,0 +1,46 @@
+package main
+
+import "time"
+
+// --- Day Span ---
+
+// DaySpan describes the shape of a single day: when meetings start and end
+// and how much of that stretch is gaps between them.
+type DaySpan struct {
+	FirstMeetingStart string `json:"first_meeting_start"`
+	LastMeetingEnd    string `json:"last_meeting_end"`
+	SpanMinutes       int    `json:"span_minutes"`
+	MeetingMinutes    int    `json:"meeting_minutes"` // overlapping meetings count once
+	GapMinutes        int    `json:"gap_minutes"`
+}
+
+// computeDaySpan covers the meetings (see blocksTime) inside [from, to),
+// clipped to it. It returns nil for a day without meetings.
+func computeDaySpan(events []SimplifiedEvent, from, to time.Time) *DaySpan {
+	var busy []interval
+	for _, b := range mergeBusy(events) {
+		if b.start.Before(from) {
+			b.start = from
+		}
+		if b.end.After(to) {
+			b.end = to
+		}
+		if b.end.After(b.start) {
+			busy = append(busy, b)
+		}
+	}
+	if len(busy) == 0 {
+		return nil
+	}
+	first, last := busy[0].start, busy[len(busy)-1].end
+	span := &DaySpan{
+		FirstMeetingStart: first.In(from.Location()).Format(time.RFC3339),
+		LastMeetingEnd:    last.In(from.Location()).Format(time.RFC3339),
+		SpanMinutes:       int(last.Sub(first).Minutes()),
+	}
+	for _, b := range busy {
+		span.MeetingMinutes += int(b.end.Sub(b.start).Minutes())
+	}
+	span.GapMinutes = span.SpanMinutes - span.MeetingMinutes
+	return span
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Declines ---
+
+// A declined meeting still sits on the calendar. The brief lists each one
+// with how full the rest of its day is: a decline on an otherwise empty day
+// may be worth revisiting, one on a packed day is the decline paying off.
+
+// packedDayShare is the share of working hours in meetings that makes a day
+// packed; declineHistoryDays is how far back --suggest-declines looks.
+const (
+	packedDayShare     = 0.6
+	declineHistoryDays = 28
+)
+
+type DeclinedEvent struct {
+	Summary           string `json:"summary"`
+	Start             string `json:"start"`
+	EventID           string `json:"event_id,omitempty"`
+	DayMeetingMinutes int    `json:"day_meeting_minutes"` // other meetings that day, merged across accounts
+	DayLoad           string `json:"day_load"`            // empty, light or packed
+}
+
+type DeclineSuggestion struct {
+	Summary       string `json:"summary"`
+	SeriesID      string `json:"series_id"`
+	Organizer     string `json:"organizer,omitempty"`
+	Instances     int    `json:"instances"`
+	Attended      int    `json:"attended"`
+	Declined      int    `json:"declined"`
+	NoResponse    int    `json:"no_response"`
+	AttendeeCount int    `json:"attendee_count,omitempty"`
+	Reason        string `json:"reason"`
+}
+
+// dayLoad classifies minutes of meetings against a working day of dayStart
+// to dayEnd.
+func dayLoad(minutes int, dayStart, dayEnd time.Duration) string {
+	switch {
+	case minutes == 0:
+		return "empty"
+	case float64(minutes) >= packedDayShare*(dayEnd-dayStart).Minutes():
+		return "packed"
+	default:
+		return "light"
+	}
+}
+
+// findDeclined pairs each declined event with the meeting load of its day.
+// blocksTime already leaves declined events out of events' busy time.
+func findDeclined(declined, events []SimplifiedEvent, loc *time.Location, dayStart, dayEnd time.Duration) []DeclinedEvent {
+	busy := mergeBusy(events)
+	var out []DeclinedEvent
+	for _, d := range declined {
+		start, err := time.Parse(time.RFC3339, d.Start)
+		if err != nil {
+			continue
+		}
+		start = start.In(loc)
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+		next := day.AddDate(0, 0, 1)
+		minutes := 0
+		for _, b := range busy {
+			if b.end.After(day) && b.start.Before(next) {
+				minutes += int(earlierOf(b.end, next).Sub(laterOf(b.start, day)).Minutes())
+			}
+		}
+		out = append(out, DeclinedEvent{
+			Summary:           d.Summary,
+			Start:             d.Start,
+			EventID:           d.EventID,
+			DayMeetingMinutes: minutes,
+			DayLoad:           dayLoad(minutes, dayStart, dayEnd),
+		})
+	}
+	return out
+}
+
+// suggestDeclines looks over past instances of recurring meetings and
+// proposes the series I rarely accept. Series I organize and 1:1s are left
+// alone: those are not the low-priority kind.
+func suggestDeclines(history []GogEvent, now time.Time) []DeclineSuggestion {
+	series := map[string]*DeclineSuggestion{}
+	var order []string
+	for _, e := range history {
+		if e.RecurringEventID == "" || e.Organizer.Self || e.Status == "cancelled" {
+			continue
+		}
+		simplified := simplifyEvent(e, "")
+		if simplified.AllDay || simplified.Size == "one_on_one" {
+			continue
+		}
+		if start, err := time.Parse(time.RFC3339, simplified.Start); err != nil || start.After(now) {
+			continue
+		}
+		s, ok := series[e.RecurringEventID]
+		if !ok {
+			s = &DeclineSuggestion{SeriesID: e.RecurringEventID, Organizer: simplified.OrganizerEmail}
+			series[e.RecurringEventID] = s
+			order = append(order, e.RecurringEventID)
+		}
+		// The latest instance names the series.
+		s.Summary, s.AttendeeCount = simplified.Summary, simplified.AttendeeCount
+		s.Instances++
+		switch extractMyResponse(e) {
+		case "accepted":
+			s.Attended++
+		case "declined":
+			s.Declined++
+		case "needsAction", "":
+			s.NoResponse++
+		}
+	}
+
+	suggestions := []DeclineSuggestion{}
+	for _, id := range order {
+		s := series[id]
+		if s.Instances < 2 || float64(s.Attended)/float64(s.Instances) >= 0.5 {
+			continue
+		}
+		switch {
+		case s.Attended == 0 && s.Declined == s.Instances:
+			s.Reason = "declined every time"
+		case s.Attended == 0:
+			s.Reason = "never accepted"
+		default:
+			s.Reason = "accepted less than half the time"
+		}
+		suggestions = append(suggestions, *s)
+	}
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		ra, rb := float64(a.Attended)/float64(a.Instances), float64(b.Attended)/float64(b.Instances)
+		if ra != rb {
+			return ra < rb
+		}
+		return a.AttendeeCount > b.AttendeeCount
+	})
+	return suggestions
+}
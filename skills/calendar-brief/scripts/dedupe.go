@@ -0,0 +1,70 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+// --- Cross-account Dedup ---
+
+// dedupeEvents merges copies of the same meeting seen by several accounts,
+// such as a work meeting my personal account is also invited to. Copies
+// share an iCalUID and start; events without a UID match on summary and
+// start instead. The first copy is kept, unless I declined it and accepted
+// another, and Accounts lists every account that sees the meeting. A room
+// booked on any copy is kept.
+func dedupeEvents(events []SimplifiedEvent) []SimplifiedEvent {
+	merged := make([]SimplifiedEvent, 0, len(events))
+	index := map[string]int{}
+	for _, e := range events {
+		key := copyKey(e.uid, e.Summary, e.Start)
+		i, seen := index[key]
+		if !seen {
+			if e.account != "" {
+				e.Accounts = []string{e.account}
+			}
+			index[key] = len(merged)
+			merged = append(merged, e)
+			continue
+		}
+		accounts := merged[i].Accounts
+		if e.account != "" && !containsString(accounts, e.account) {
+			accounts = append(accounts, e.account)
+		}
+		room := merged[i].Room
+		if room == nil {
+			room = e.Room
+		}
+		if merged[i].Response == "declined" && e.Response != "declined" {
+			merged[i] = e
+		}
+		merged[i].Accounts = accounts
+		// Only the organizer's copy may list the room.
+		if merged[i].Room == nil {
+			merged[i].Room = room
+		}
+	}
+	return merged
+}
+
+// copyKey identifies one meeting across the accounts that see it: its
+// iCalUID and start, or its summary and start when it has no UID. Copies may
+// carry the start in different offsets.
+func copyKey(uid, summary, start string) string {
+	if t, err := time.Parse(time.RFC3339, start); err == nil {
+		start = t.UTC().Format(time.RFC3339)
+	}
+	if uid == "" {
+		return "summary\x00" + strings.ToLower(strings.TrimSpace(summary)) + "\x00" + start
+	}
+	return "uid\x00" + uid + "\x00" + start
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDedupeEvents(t *testing.T) {
+	room := &Room{Name: "Atlas"}
+	events := []SimplifiedEvent{
+		// The same meeting on two accounts, in different offsets.
+		{EventID: "p2", Summary: "Design review", Start: "2026-10-17T14:00:00+09:00", uid: "review@corp", account: "me@gmail.com", Response: "declined"},
+		{EventID: "w2", Summary: "Design review", Start: "2026-10-17T05:00:00Z", uid: "review@corp", account: "me@corp.example", Response: "accepted", Room: room},
+		// Same UID at another time is another occurrence.
+		{EventID: "w6", Summary: "Design review", Start: "2026-10-24T14:00:00+09:00", uid: "review@corp", account: "me@corp.example"},
+		// No UID: summary and start decide, ignoring case and spaces.
+		{EventID: "p4", Summary: "Lunch ", Start: "2026-10-17T12:00:00+09:00", account: "me@gmail.com"},
+		{EventID: "w7", Summary: "lunch", Start: "2026-10-17T12:00:00+09:00", account: "me@corp.example"},
+		{EventID: "w8", Summary: "Lunch", Start: "2026-10-17T12:30:00+09:00", account: "me@corp.example"},
+	}
+	got := dedupeEvents(events)
+	want := []struct {
+		id       string
+		accounts string
+	}{
+		{"w2", "[me@gmail.com me@corp.example]"}, // the accepted copy replaces the declined one
+		{"w6", "[me@corp.example]"},
+		{"p4", "[me@gmail.com me@corp.example]"},
+		{"w8", "[me@corp.example]"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		if got[i].EventID != w.id || fmt.Sprint(got[i].Accounts) != w.accounts {
+			t.Errorf("event %d: got %s %v, want %s %s", i, got[i].EventID, got[i].Accounts, w.id, w.accounts)
+		}
+	}
+	if got[0].Room != room {
+		t.Errorf("room = %v, want the organizer copy's room", got[0].Room)
+	}
+}
+
+func TestDedupeEventsKeepsRoom(t *testing.T) {
+	room := &Room{Name: "Atlas"}
+	got := dedupeEvents([]SimplifiedEvent{
+		{EventID: "w2", Start: "2026-10-17T14:00:00+09:00", uid: "r", account: "a", Response: "declined", Room: room},
+		{EventID: "p2", Start: "2026-10-17T14:00:00+09:00", uid: "r", account: "b", Response: "accepted"},
+	})
+	if len(got) != 1 || got[0].EventID != "p2" || got[0].Room != room {
+		t.Errorf("got %+v, want p2 with w2's room", got)
+	}
+}
+
+func TestFindPrepNeedsSharedInterview(t *testing.T) {
+	interview := GogEvent{
+		ICalUID:   "interview@corp",
+		Summary:   "Interview: backend candidate",
+		Start:     GogEventTime{DateTime: "2026-10-19T15:00:00+09:00"},
+		End:       GogEventTime{DateTime: "2026-10-19T16:00:00+09:00"},
+		Attendees: []GogAttendee{{Email: "me@corp.example"}, {Email: "candidate@example.org"}},
+	}
+	var candidates []PrepBlock
+	for _, account := range []string{"me@corp.example", "me@gmail.com"} {
+		copyOf := interview
+		if account == "me@gmail.com" {
+			copyOf.Start.DateTime = "2026-10-19T06:00:00Z" // the personal calendar reports UTC
+		}
+		c, ok := newPrepCandidate(copyOf, simplifyEvent(copyOf, "work"), account)
+		if !ok {
+			t.Fatalf("%s: interview is not a prep candidate", account)
+		}
+		candidates = append(candidates, c)
+	}
+
+	got := findPrepNeeds(candidates, nil)
+	if len(got) != 1 {
+		t.Fatalf("got %d prep entries, want 1: %+v", len(got), got)
+	}
+	if got[0].account != "me@corp.example" {
+		t.Errorf("account = %s, want the first account's copy", got[0].account)
+	}
+	if fmt.Sprint(got[0].Tags) != "[interview external]" || got[0].PrepMinutes != prepMinutesByTag["interview"] {
+		t.Errorf("got tags %v and %d minutes", got[0].Tags, got[0].PrepMinutes)
+	}
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// --- Event Descriptions ---
+
+const defaultDescriptionLimit = 280
+
+// descriptionFlag makes --description work both bare (default length) and
+// as --description=N.
+type descriptionFlag int
+
+func (d *descriptionFlag) String() string { return strconv.Itoa(int(*d)) }
+
+func (d *descriptionFlag) Set(s string) error {
+	switch s {
+	case "true":
+		*d = defaultDescriptionLimit
+	case "false":
+		*d = 0
+	default:
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return strconv.ErrSyntax
+		}
+		*d = descriptionFlag(n)
+	}
+	return nil
+}
+
+func (d *descriptionFlag) IsBoolFlag() bool { return true }
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|h\d)>`)
+	htmlTagPattern   = regexp.MustCompile(`<[^>]*>`)
+)
+
+// plainDescription strips HTML from an event description, collapses
+// whitespace, and cuts it to limit characters.
+func plainDescription(s string, limit int) string {
+	s = htmlBreakPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(htmlTagPattern.ReplaceAllString(s, ""))
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > limit {
+		s = strings.TrimSpace(string(r[:limit])) + "…"
+	}
+	return s
+}
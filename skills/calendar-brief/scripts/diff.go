@@ -0,0 +1,98 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Diff Against the Previous Run ---
+
+// With --diff, each run saves what it saw per date range in
+// last-brief.json and reports how the same range changed since the previous
+// --diff run. The snapshot is taken before any output filter, so changing
+// flags between runs or declining a meeting does not read as a change.
+
+type EventChange struct {
+	Change        string `json:"change"` // added, time_changed or cancelled
+	Summary       string `json:"summary"`
+	Start         string `json:"start"`
+	End           string `json:"end"`
+	PreviousStart string `json:"previous_start,omitempty"`
+	PreviousEnd   string `json:"previous_end,omitempty"`
+	EventID       string `json:"event_id"`
+}
+
+type briefSnapshot struct {
+	Saved  string                   `json:"saved"`
+	Events map[string]snapshotEvent `json:"events"`
+}
+
+// diffEvents returns the changes since the previous snapshot of the range
+// and when that snapshot was taken. events is everything fetched, unfiltered;
+// failed lists the accounts that could not be read this time, whose previous
+// events are carried over unchanged. The first run for a range reports no
+// changes and an empty since. Events that disappeared are reported as
+// cancelled, since Google drops deleted events from listings.
+func diffEvents(events []SimplifiedEvent, failed []string, from, to, now time.Time) ([]EventChange, string) {
+	rangeKey := from.Format(time.RFC3339) + "/" + to.Format(time.RFC3339)
+	snapshots := map[string]briefSnapshot{}
+	loadState("last-brief.json", &snapshots)
+	previous, seeded := snapshots[rangeKey]
+
+	current := map[string]snapshotEvent{}
+	for _, e := range events {
+		if _, ok := current[e.EventID]; e.EventID != "" && !ok {
+			current[e.EventID] = snapshotEvent{Summary: e.Summary, Start: e.Start, End: e.End, Status: e.Status, Account: e.account}
+		}
+	}
+	carried := map[string]bool{}
+	for id, before := range previous.Events {
+		if _, ok := current[id]; !ok && containsString(failed, before.Account) {
+			current[id], carried[id] = before, true
+		}
+	}
+	snapshots[rangeKey] = briefSnapshot{Saved: now.Format(time.RFC3339), Events: current}
+	saveState("last-brief.json", snapshots)
+	if !seeded {
+		return []EventChange{}, ""
+	}
+
+	changes := []EventChange{}
+	reported := map[string]bool{}
+	for _, e := range events {
+		cur, ok := current[e.EventID]
+		if !ok || carried[e.EventID] || reported[e.EventID] {
+			continue
+		}
+		reported[e.EventID] = true
+		change := EventChange{Summary: cur.Summary, Start: cur.Start, End: cur.End, EventID: e.EventID}
+		before, existed := previous.Events[e.EventID]
+		switch {
+		case !existed:
+			change.Change = "added"
+		case cur.Status == "cancelled" && before.Status != "cancelled":
+			change.Change = "cancelled"
+		case !sameInstant(before.Start, cur.Start) || !sameInstant(before.End, cur.End):
+			change.Change = "time_changed"
+			change.PreviousStart, change.PreviousEnd = before.Start, before.End
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	var gone []string
+	for id := range previous.Events {
+		if _, ok := current[id]; !ok {
+			gone = append(gone, id)
+		}
+	}
+	sort.Strings(gone)
+	for _, id := range gone {
+		before := previous.Events[id]
+		if before.Status == "cancelled" {
+			continue
+		}
+		changes = append(changes, EventChange{Change: "cancelled", Summary: before.Summary, Start: before.Start, End: before.End, EventID: id})
+	}
+	return changes, previous.Saved
+}
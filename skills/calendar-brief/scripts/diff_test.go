@@ -0,0 +1,52 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// --- Diff Against the Previous Run ---
+
+func TestDiffEvents(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	from := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	now := from.Add(-48 * time.Hour)
+	event := func(id, account, start, end string) SimplifiedEvent {
+		return SimplifiedEvent{EventID: id, Summary: id, Start: start, End: end, Status: "confirmed", account: account}
+	}
+	standup := event("standup", "me@corp.example", "2026-10-19T09:00:00Z", "2026-10-19T09:15:00Z")
+	review := event("review", "me@corp.example", "2026-10-19T14:00:00Z", "2026-10-19T15:00:00Z")
+	dentist := event("dentist", "me@gmail.example", "2026-10-19T17:00:00Z", "2026-10-19T18:00:00Z")
+
+	changes, since := diffEvents([]SimplifiedEvent{standup, review, dentist}, nil, from, to, now)
+	if len(changes) != 0 || since != "" {
+		t.Fatalf("first run: changes %+v since %q, want none", changes, since)
+	}
+
+	// The personal account fails: its dentist appointment is not cancelled.
+	moved := review
+	moved.Start, moved.End = "2026-10-19T16:00:00Z", "2026-10-19T17:00:00Z"
+	planning := event("planning", "me@corp.example", "2026-10-19T11:00:00Z", "2026-10-19T12:00:00Z")
+	changes, since = diffEvents([]SimplifiedEvent{standup, moved, planning}, []string{"me@gmail.example"}, from, to, now.Add(time.Hour))
+	if since != now.Format(time.RFC3339) {
+		t.Errorf("since = %q, want %q", since, now.Format(time.RFC3339))
+	}
+	got := map[string]string{}
+	for _, c := range changes {
+		got[c.EventID] = c.Change
+	}
+	want := map[string]string{"review": "time_changed", "planning": "added"}
+	if len(got) != len(want) || got["review"] != want["review"] || got["planning"] != want["planning"] {
+		t.Fatalf("second run: changes %v, want %v", got, want)
+	}
+
+	// Back again, the standup is gone and nothing else moved.
+	changes, _ = diffEvents([]SimplifiedEvent{moved, planning, dentist}, nil, from, to, now.Add(2*time.Hour))
+	if len(changes) != 1 || changes[0].EventID != "standup" || changes[0].Change != "cancelled" {
+		t.Fatalf("third run: changes %+v, want only standup cancelled", changes)
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Double-booked Slots ---
+
+type DoubleBookedSlot struct {
+	Start   string     `json:"start"`
+	End     string     `json:"end"`
+	Minutes int        `json:"minutes"`
+	Events  []EventRef `json:"events"` // every accepted event overlapping the slot
+}
+
+type DoubleBookedOutput struct {
+	Timezone     string             `json:"timezone,omitempty"`
+	DoubleBooked []DoubleBookedSlot `json:"double_booked"`
+	Errors       []AccountError     `json:"errors,omitempty"`
+	Meta         *Meta              `json:"meta,omitempty"`
+}
+
+// committed reports whether I am going to an event: accepted, or my own
+// event with no invitation to answer. Tentative and unanswered ones are
+// not double-bookings yet.
+func committed(e SimplifiedEvent) bool {
+	return e.Response == "accepted" || e.Response == ""
+}
+
+// findDoubleBooked returns the maximal time ranges covered by two or more
+// committed events. Events should already be deduplicated across accounts.
+func findDoubleBooked(events []SimplifiedEvent, loc *time.Location) []DoubleBookedSlot {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	type span struct {
+		start, end time.Time
+		ref        EventRef
+	}
+	var edges []edge
+	var spans []span
+	for _, e := range events {
+		start, end, ok := blocksTime(e)
+		if !ok || !committed(e) {
+			continue
+		}
+		edges = append(edges, edge{start, 1}, edge{end, -1})
+		spans = append(spans, span{start, end, refOf(e)})
+	}
+	// Ends sort before starts at the same instant, so back-to-back meetings
+	// do not count as overlapping.
+	sort.Slice(edges, func(i, j int) bool {
+		if !edges[i].at.Equal(edges[j].at) {
+			return edges[i].at.Before(edges[j].at)
+		}
+		return edges[i].delta < edges[j].delta
+	})
+
+	slots := []DoubleBookedSlot{}
+	depth := 0
+	var open time.Time
+	for _, ed := range edges {
+		depth += ed.delta
+		switch {
+		case ed.delta > 0 && depth == 2:
+			open = ed.at
+		case ed.delta < 0 && depth == 1:
+			slot := DoubleBookedSlot{
+				Start:   open.In(loc).Format(time.RFC3339),
+				End:     ed.at.In(loc).Format(time.RFC3339),
+				Minutes: int(ed.at.Sub(open).Minutes()),
+			}
+			for _, s := range spans {
+				if s.start.Before(ed.at) && s.end.After(open) {
+					slot.Events = append(slot.Events, s.ref)
+				}
+			}
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
@@ -0,0 +1,53 @@
+package main
+
+import "time"
+
+// --- Durations ---
+
+// durationMinutes is the length of an event: end minus start for timed
+// events, whole days for all-day dates. Unparseable or inverted times give 0.
+func durationMinutes(start, end string) int {
+	for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+		s, err1 := time.Parse(layout, start)
+		e, err2 := time.Parse(layout, end)
+		if err1 == nil && err2 == nil {
+			if e.After(s) {
+				return int(e.Sub(s).Minutes())
+			}
+			return 0
+		}
+	}
+	return 0
+}
+
+// setStartsIn records how many minutes from now each timed event starts.
+func setStartsIn(events []SimplifiedEvent, now time.Time) {
+	for i := range events {
+		start, err := time.Parse(time.RFC3339, events[i].Start)
+		if err != nil {
+			continue
+		}
+		minutes := int(start.Sub(now).Minutes())
+		events[i].StartsInMinutes = &minutes
+	}
+}
+
+// nextHorizon is how far ahead --next looks for upcoming events.
+const nextHorizon = 14 * 24 * time.Hour
+
+// upcomingEvents keeps the first n timed, not-cancelled events that start
+// after now; events must already be sorted.
+func upcomingEvents(events []SimplifiedEvent, now time.Time, n int) []SimplifiedEvent {
+	upcoming := []SimplifiedEvent{}
+	for _, e := range events {
+		if len(upcoming) == n {
+			break
+		}
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil || e.Status == "cancelled" || !start.After(now) {
+			continue
+		}
+		upcoming = append(upcoming, e)
+	}
+	return upcoming
+}
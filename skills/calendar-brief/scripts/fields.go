@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// --- Field Selection ---
+
+// jsonFieldNames lists the JSON keys of a struct type, in declaration order.
+func jsonFieldNames(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// parseFields splits a comma-separated --fields value and checks each name
+// against the JSON keys of item's type.
+func parseFields(spec string, item interface{}) ([]string, error) {
+	valid := jsonFieldNames(reflect.TypeOf(item))
+	var fields []string
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		known := false
+		for _, v := range valid {
+			if v == f {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown field %q (valid: %s)", f, strings.Join(valid, ", "))
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--fields is empty")
+	}
+	return fields, nil
+}
+
+// projectFields reduces each item to the selected keys. Keys an item omits
+// (omitempty) stay absent.
+func projectFields(items interface{}, fields []string) []map[string]interface{} {
+	data, _ := json.Marshal(items)
+	var full []map[string]interface{}
+	json.Unmarshal(data, &full)
+
+	projected := make([]map[string]interface{}, 0, len(full))
+	for _, item := range full {
+		p := make(map[string]interface{}, len(fields))
+		for _, f := range fields {
+			if v, ok := item[f]; ok {
+				p[f] = v
+			}
+		}
+		projected = append(projected, p)
+	}
+	return projected
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// --- Keyword Filtering ---
+
+// keywordFilter keeps events whose summary, description or location match
+// --match and drops those matching --exclude. Both are case-insensitive
+// regular expressions, so a plain substring such as "1:1" works as is.
+type keywordFilter struct {
+	match, exclude *regexp.Regexp
+}
+
+func newKeywordFilter(match, exclude string) (keywordFilter, error) {
+	var f keywordFilter
+	var err error
+	if match != "" {
+		if f.match, err = regexp.Compile("(?i)" + match); err != nil {
+			return f, fmt.Errorf("--match: %v", err)
+		}
+	}
+	if exclude != "" {
+		if f.exclude, err = regexp.Compile("(?i)" + exclude); err != nil {
+			return f, fmt.Errorf("--exclude: %v", err)
+		}
+	}
+	return f, nil
+}
+
+func (f keywordFilter) keep(event GogEvent) bool {
+	if f.match == nil && f.exclude == nil {
+		return true
+	}
+	text := event.Summary + "\n" + event.Description + "\n" + event.Location
+	if f.match != nil && !f.match.MatchString(text) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.MatchString(text)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Free Slots ---
+
+type FreeSlot struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Minutes int    `json:"minutes"`
+}
+
+type interval struct {
+	start, end time.Time
+}
+
+// parseClock parses "HH:MM" into an offset from midnight.
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (want HH:MM)", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// resolveWorkingHours picks working hours from the --work-hours flag
+// ("HH:MM-HH:MM"), then config, then the 09:00-18:00 default.
+func resolveWorkingHours(flagValue string, cfg WorkingHours) (time.Duration, time.Duration, error) {
+	startStr, endStr := "09:00", "18:00"
+	if cfg.Start != "" {
+		startStr = cfg.Start
+	}
+	if cfg.End != "" {
+		endStr = cfg.End
+	}
+	if flagValue != "" {
+		parts := strings.SplitN(flagValue, "-", 2)
+		if len(parts) != 2 {
+			return 0, 0, fmt.Errorf("invalid --work-hours %q (want HH:MM-HH:MM)", flagValue)
+		}
+		startStr, endStr = parts[0], parts[1]
+	}
+
+	start, err := parseClock(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClock(endStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("working hours end %s is not after start %s", endStr, startStr)
+	}
+	return start, end, nil
+}
+
+// mergeBusy collapses the time-blocking events into sorted, non-overlapping
+// intervals.
+func mergeBusy(events []SimplifiedEvent) []interval {
+	var busy []interval
+	for _, e := range events {
+		if start, end, ok := blocksTime(e); ok {
+			busy = append(busy, interval{start, end})
+		}
+	}
+	sort.Slice(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+
+	merged := make([]interval, 0, len(busy))
+	for _, b := range busy {
+		if n := len(merged); n > 0 && !b.start.After(merged[n-1].end) {
+			if b.end.After(merged[n-1].end) {
+				merged[n-1].end = b.end
+			}
+			continue
+		}
+		merged = append(merged, b)
+	}
+	return merged
+}
+
+// findFreeSlots returns the gaps of at least minGap inside working hours on
+// each weekday of [from, to), ignoring time that has already passed and the
+// days in off.
+func findFreeSlots(events []SimplifiedEvent, from, to, now time.Time, dayStart, dayEnd, minGap time.Duration, off map[string]DayOff) []FreeSlot {
+	busy := mergeBusy(events)
+	slots := []FreeSlot{}
+
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if _, ok := off[day.Format("2006-01-02")]; ok || !isBusinessDay(day, nil) {
+			continue
+		}
+		cursor := day.Add(dayStart)
+		end := day.Add(dayEnd)
+		if cursor.Before(now) {
+			cursor = now.Truncate(time.Minute)
+		}
+
+		emit := func(gapEnd time.Time) {
+			if gapEnd.Sub(cursor) >= minGap && gapEnd.After(cursor) {
+				slots = append(slots, FreeSlot{
+					Start:   cursor.Format(time.RFC3339),
+					End:     gapEnd.Format(time.RFC3339),
+					Minutes: int(gapEnd.Sub(cursor).Minutes()),
+				})
+			}
+		}
+		for _, b := range busy {
+			if !b.end.After(cursor) || !b.start.Before(end) {
+				continue
+			}
+			if b.start.After(cursor) {
+				emit(b.start)
+			}
+			cursor = b.end
+		}
+		if cursor.Before(end) {
+			emit(end)
+		}
+	}
+	return slots
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindFreeSlots(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Seoul")
+	from := time.Date(2026, 10, 16, 0, 0, 0, 0, loc) // Friday
+	to := from.AddDate(0, 0, 4)                      // through Monday
+	at := func(day, clock string) string { return "2026-10-" + day + "T" + clock + ":00+09:00" }
+	events := []SimplifiedEvent{
+		{Start: at("16", "10:00"), End: at("16", "11:00")},
+		{Start: at("16", "10:30"), End: at("16", "12:00")}, // overlaps the one before
+		{Start: at("16", "12:10"), End: at("16", "13:00")}, // leaves a 10-minute gap
+		{Start: at("16", "15:00"), End: at("16", "16:00"), Response: "declined"},
+		{Start: at("16", "17:00"), End: at("16", "19:00")}, // runs past working hours
+		{Start: "2026-10-19", End: "2026-10-20", AllDay: true},
+	}
+	slot := func(s FreeSlot) string {
+		start, _ := time.Parse(time.RFC3339, s.Start)
+		end, _ := time.Parse(time.RFC3339, s.End)
+		return fmt.Sprintf("%s %s-%s", start.Format("01-02"), start.Format("15:04"), end.Format("15:04"))
+	}
+	list := func(slots []FreeSlot) string {
+		var out []string
+		for _, s := range slots {
+			out = append(out, slot(s))
+		}
+		return strings.Join(out, ", ")
+	}
+
+	// Friday morning: the weekend and the off Monday are skipped.
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, loc)
+	off := map[string]DayOff{"2026-10-19": {Date: "2026-10-19", Reason: "holiday"}}
+	got := list(findFreeSlots(events, from, to, now, 9*time.Hour, 18*time.Hour, 30*time.Minute, off))
+	if want := "10-16 09:00-10:00, 10-16 13:00-17:00"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Mid-afternoon: time already passed is not free, and Monday counts.
+	now = time.Date(2026, 10, 16, 14, 20, 30, 0, loc)
+	got = list(findFreeSlots(events, from, to, now, 9*time.Hour, 18*time.Hour, 30*time.Minute, nil))
+	if want := "10-16 14:20-17:00, 10-19 09:00-18:00"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// A minimum gap longer than any slot finds nothing.
+	if got := findFreeSlots(events, from, from.AddDate(0, 0, 1), now, 9*time.Hour, 18*time.Hour, 4*time.Hour, nil); len(got) != 0 {
+		t.Errorf("got %s, want no slots", list(got))
+	}
+}
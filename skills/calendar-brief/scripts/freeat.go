@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Availability Check ---
+
+// freeAtSearchDays is how far past the requested day --free-at looks for
+// alternatives, and freeAtAlternatives how many it offers.
+const (
+	freeAtSearchDays   = 7
+	freeAtAlternatives = 3
+)
+
+type FreeAtOutput struct {
+	Timezone     string         `json:"timezone,omitempty"`
+	Slot         FreeSlot       `json:"slot"`
+	Free         bool           `json:"free"`
+	Conflicts    []EventRef     `json:"conflicts"`
+	Alternatives []FreeSlot     `json:"alternatives,omitempty"` // nearest free slots of the same length, when busy
+	Errors       []AccountError `json:"errors,omitempty"`
+	Meta         *Meta          `json:"meta,omitempty"`
+}
+
+var dateTimeLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006/01/02 15:04", time.RFC3339}
+
+// parseDateTime reads a date and time of day, such as the --free-at slot,
+// in loc.
+func parseDateTime(s string, loc *time.Location) (time.Time, error) {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (want \"YYYY-MM-DD HH:MM\")", s)
+}
+
+// checkFreeAt reports whether [start, start+length) is clear on every
+// account. When it is not, it lists the events in the way and the free
+// working-hours slots, one per gap, whose start is closest to the request.
+func checkFreeAt(events []SimplifiedEvent, start time.Time, length time.Duration, now, searchTo time.Time, dayStart, dayEnd time.Duration) FreeAtOutput {
+	end := start.Add(length)
+	output := FreeAtOutput{
+		Slot:      FreeSlot{Start: start.Format(time.RFC3339), End: end.Format(time.RFC3339), Minutes: int(length.Minutes())},
+		Conflicts: []EventRef{},
+	}
+	for _, e := range events {
+		if s, en, ok := blocksTime(e); ok && s.Before(end) && en.After(start) {
+			output.Conflicts = append(output.Conflicts, refOf(e))
+		}
+	}
+	output.Free = len(output.Conflicts) == 0
+	if output.Free {
+		return output
+	}
+
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	type candidate struct {
+		start    time.Time
+		distance time.Duration
+	}
+	var candidates []candidate
+	for _, gap := range findFreeSlots(events, day, searchTo, now, dayStart, dayEnd, length, nil) {
+		gapStart, err1 := time.Parse(time.RFC3339, gap.Start)
+		gapEnd, err2 := time.Parse(time.RFC3339, gap.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		// The start in this gap nearest the requested one.
+		c := start
+		if c.Before(gapStart) {
+			c = gapStart
+		}
+		if latest := gapEnd.Add(-length); c.After(latest) {
+			c = latest
+		}
+		distance := c.Sub(start)
+		if distance < 0 {
+			distance = -distance
+		}
+		candidates = append(candidates, candidate{c.In(start.Location()), distance})
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+	for i := 0; i < len(candidates) && i < freeAtAlternatives; i++ {
+		c := candidates[i].start
+		output.Alternatives = append(output.Alternatives, FreeSlot{
+			Start:   c.Format(time.RFC3339),
+			End:     c.Add(length).Format(time.RFC3339),
+			Minutes: int(length.Minutes()),
+		})
+	}
+	return output
+}
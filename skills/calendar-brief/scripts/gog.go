@@ -0,0 +1,98 @@
+package main
+
+// --- gog Response Models ---
+
+// These mirror the parts of gog's --json output that the brief reads.
+// Fields gog adds later are ignored by encoding/json. A list item with a
+// known field of an unexpected type is kept by decodeItems with that field
+// left empty, and counted in the account's meta as malformed, instead of
+// failing the whole list. omitempty keeps the fallback cache small.
+
+// GogEvent is a Google Calendar event as gog prints it.
+type GogEvent struct {
+	ID               string          `json:"id,omitempty"`
+	ICalUID          string          `json:"iCalUID,omitempty"`
+	RecurringEventID string          `json:"recurringEventId,omitempty"`
+	HTMLLink         string          `json:"htmlLink,omitempty"`
+	Summary          string          `json:"summary,omitempty"`
+	Description      string          `json:"description,omitempty"`
+	Location         string          `json:"location,omitempty"`
+	Status           string          `json:"status,omitempty"`
+	EventType        string          `json:"eventType,omitempty"`
+	ColorID          string          `json:"colorId,omitempty"`
+	Visibility       string          `json:"visibility,omitempty"` // default, public, private or confidential
+	HangoutLink      string          `json:"hangoutLink,omitempty"`
+	Start            GogEventTime    `json:"start"`
+	End              GogEventTime    `json:"end"`
+	Organizer        GogPerson       `json:"organizer"`
+	Attendees        []GogAttendee   `json:"attendees,omitempty"`
+	ConferenceData   *GogConference  `json:"conferenceData,omitempty"`
+	Attachments      []GogAttachment `json:"attachments,omitempty"`
+	Reminders        *GogReminders   `json:"reminders,omitempty"`
+}
+
+// GogEventTime holds dateTime for timed events and date for all-day ones.
+type GogEventTime struct {
+	DateTime string `json:"dateTime,omitempty"`
+	Date     string `json:"date,omitempty"`
+}
+
+// value returns whichever of dateTime and date is set.
+func (t GogEventTime) value() string {
+	if t.DateTime != "" {
+		return t.DateTime
+	}
+	return t.Date
+}
+
+type GogPerson struct {
+	Email       string `json:"email,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	Self        bool   `json:"self,omitempty"`
+}
+
+type GogAttendee struct {
+	Email          string `json:"email,omitempty"`
+	DisplayName    string `json:"displayName,omitempty"`
+	ResponseStatus string `json:"responseStatus,omitempty"`
+	Comment        string `json:"comment,omitempty"`
+	Self           bool   `json:"self,omitempty"`
+	Optional       bool   `json:"optional,omitempty"`
+	Resource       bool   `json:"resource,omitempty"` // a room or other bookable resource
+}
+
+// GogReminders is useDefault for the calendar's default reminders, or the
+// event's own overrides; neither means no reminder at all.
+type GogReminders struct {
+	UseDefault bool          `json:"useDefault,omitempty"`
+	Overrides  []GogReminder `json:"overrides,omitempty"`
+}
+
+type GogReminder struct {
+	Method  string `json:"method,omitempty"` // popup or email
+	Minutes int    `json:"minutes,omitempty"`
+}
+
+type GogConference struct {
+	EntryPoints []GogEntryPoint `json:"entryPoints,omitempty"`
+}
+
+type GogEntryPoint struct {
+	EntryPointType string `json:"entryPointType,omitempty"`
+	URI            string `json:"uri,omitempty"`
+}
+
+type GogAttachment struct {
+	FileURL string `json:"fileUrl,omitempty"`
+	Title   string `json:"title,omitempty"`
+}
+
+// GogMessage is a Gmail search result as gog prints it.
+type GogMessage struct {
+	ID       string `json:"id,omitempty"`
+	ThreadID string `json:"threadId,omitempty"`
+	Date     string `json:"date,omitempty"`
+	From     string `json:"from,omitempty"`
+	Subject  string `json:"subject,omitempty"`
+	Snippet  string `json:"snippet,omitempty"`
+}
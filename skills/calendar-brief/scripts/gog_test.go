@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// --- gog Response Models ---
+
+func TestParseEventsPageTolerant(t *testing.T) {
+	const page = `{
+	  "kind": "calendar#events",
+	  "events": [
+	    {"id": "a", "summary": "Standup", "start": {"dateTime": "2026-10-19T09:00:00Z", "timeZone": "UTC"},
+	     "end": {"dateTime": "2026-10-19T09:15:00Z"}, "reminders": {"useDefault": true},
+	     "attendees": [{"email": "me@corp.example", "self": true, "responseStatus": "accepted", "additionalGuests": 0}]},
+	    {"id": "b", "summary": 42},
+	    "not an event",
+	    {"id": "c", "start": {"date": "2026-10-20"}, "end": {"date": "2026-10-21"}}
+	  ],
+	  "nextPageToken": "p2"
+	}`
+	list, err := decodeList[GogEvent](strings.NewReader(page), "events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.NextPageToken != "p2" {
+		t.Errorf("next page token = %q, want p2", list.NextPageToken)
+	}
+	events := list.Items
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Fatalf("decoded events %s, want a,b,c (unknown fields ignored, mistyped fields left empty)", got)
+	}
+	if list.Malformed != 2 {
+		t.Errorf("Malformed = %d, want 2 (the mistyped summary and the string)", list.Malformed)
+	}
+	if events[1].Summary != "" {
+		t.Errorf("mistyped summary decoded as %q", events[1].Summary)
+	}
+
+	standup := simplifyEvent(events[0], "work")
+	if standup.Start != "2026-10-19T09:00:00Z" || standup.DurationMinutes != 15 || standup.Response != "accepted" {
+		t.Errorf("simplified standup = %+v", standup)
+	}
+	if allDay := simplifyEvent(events[2], "work"); !allDay.AllDay || allDay.Summary != "(No title)" {
+		t.Errorf("simplified all-day event = %+v", allDay)
+	}
+}
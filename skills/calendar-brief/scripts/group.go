@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// --- Ordering & Grouping ---
+
+type EventGroup struct {
+	Key    string      `json:"key"`
+	Events interface{} `json:"events"` // []SimplifiedEvent, or projected maps with --fields
+}
+
+// eventStart parses an event's start; all-day dates start at midnight in loc.
+func eventStart(e SimplifiedEvent, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, e.Start); err == nil {
+		return t, true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", e.Start, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// sortEvents orders events by start time across accounts. The sort is stable,
+// so events starting together keep their account order; unparseable starts
+// go last.
+func sortEvents(events []SimplifiedEvent, loc *time.Location) {
+	sort.SliceStable(events, func(i, j int) bool {
+		a, okA := eventStart(events[i], loc)
+		b, okB := eventStart(events[j], loc)
+		if okA != okB {
+			return okA
+		}
+		return a.Before(b)
+	})
+}
+
+func groupKey(e SimplifiedEvent, by string, loc *time.Location) string {
+	switch by {
+	case "account":
+		return e.account
+	case "calendar":
+		return e.calendar
+	case "week":
+		if t, ok := eventStart(e, loc); ok {
+			year, week := t.In(loc).ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}
+		return e.Start
+	default:
+		if t, ok := eventStart(e, loc); ok {
+			return t.In(loc).Format("2006-01-02")
+		}
+		return e.Start
+	}
+}
+
+// groupEvents splits already-sorted events into sections keyed by day
+// (YYYY-MM-DD in loc), ISO week (2026-W42), account email, or calendar. Sections appear in the
+// order of their first event.
+func groupEvents(events []SimplifiedEvent, by string, loc *time.Location) []EventGroup {
+	var keys []string
+	sections := map[string][]SimplifiedEvent{}
+	for _, e := range events {
+		key := groupKey(e, by, loc)
+		if _, ok := sections[key]; !ok {
+			keys = append(keys, key)
+		}
+		sections[key] = append(sections[key], e)
+	}
+	groups := make([]EventGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, EventGroup{Key: key, Events: sections[key]})
+	}
+	return groups
+}
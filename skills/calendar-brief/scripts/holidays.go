@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// --- Holidays & Days Off ---
+
+type DayOff struct {
+	Date   string `json:"date"`
+	Reason string `json:"reason"` // holiday or out_of_office
+	Name   string `json:"name,omitempty"`
+}
+
+var outOfOfficePattern = regexp.MustCompile(`(?i)\b(ooo|out of (the )?office|vacation|pto|day off|annual leave)\b|휴가|연차|휴무|부재`)
+
+func isOutOfOffice(event GogEvent, summary string) bool {
+	return event.EventType == "outOfOffice" || outOfOfficePattern.MatchString(summary)
+}
+
+// fetchHolidays reads a public holiday calendar through account. It is a
+// no-op when no calendar is configured.
+func fetchHolidays(calendarID, accountEmail string, gogDateArgs []string) ([]SimplifiedEvent, error) {
+	if calendarID == "" {
+		return nil, nil
+	}
+	list, _, err := fetchCalendarEvents(context.Background(), calendarID, accountEmail, gogDateArgs, eventPageSize)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]SimplifiedEvent, 0, len(list.Items))
+	for _, e := range list.Items {
+		events = append(events, simplifyEvent(e, ""))
+	}
+	return events, nil
+}
+
+// allDayDates lists the dates an all-day event covers; its end date is
+// exclusive. Timed events yield nothing, since they already block their hours.
+func allDayDates(e SimplifiedEvent, loc *time.Location) []string {
+	start, err := time.ParseInLocation("2006-01-02", e.Start, loc)
+	if err != nil {
+		return nil
+	}
+	end, err := time.ParseInLocation("2006-01-02", e.End, loc)
+	if err != nil || !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+	var dates []string
+	for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format("2006-01-02"))
+	}
+	return dates
+}
+
+// findDaysOff collects configured holidays, holiday-calendar days, and days
+// covered by my own all-day out-of-office events.
+func findDaysOff(events, holidayEvents []SimplifiedEvent, holidays []string, loc *time.Location) map[string]DayOff {
+	off := map[string]DayOff{}
+	for _, d := range holidays {
+		off[d] = DayOff{Date: d, Reason: "holiday"}
+	}
+	for _, e := range holidayEvents {
+		for _, d := range allDayDates(e, loc) {
+			off[d] = DayOff{Date: d, Reason: "holiday", Name: e.Summary}
+		}
+	}
+	for _, e := range events {
+		if !e.outOfOffice || e.Status == "cancelled" || e.Response == "declined" {
+			continue
+		}
+		for _, d := range allDayDates(e, loc) {
+			if _, ok := off[d]; !ok {
+				off[d] = DayOff{Date: d, Reason: "out_of_office", Name: e.Summary}
+			}
+		}
+	}
+	return off
+}
+
+// daysOffIn returns the days off inside [from, to), sorted by date.
+func daysOffIn(off map[string]DayOff, from, to time.Time) []DayOff {
+	var days []DayOff
+	for d, o := range off {
+		if d >= from.Format("2006-01-02") && d < to.Format("2006-01-02") {
+			days = append(days, o)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days
+}
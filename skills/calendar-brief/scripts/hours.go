@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- Per-account Working Hours ---
+
+// schedule is one account's working window: [start, end) after midnight on
+// each of days.
+type schedule struct {
+	start, end time.Duration
+	days       map[time.Weekday]bool
+}
+
+var weekdays = map[time.Weekday]bool{
+	time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+}
+
+// resolveSchedule turns a working_hours config into a schedule. Unset hours
+// fall back to start/end and unset days to Monday through Friday.
+func resolveSchedule(wh WorkingHours, start, end time.Duration) (schedule, error) {
+	s := schedule{start: start, end: end, days: weekdays}
+	var err error
+	if wh.Start != "" {
+		if s.start, err = parseClock(wh.Start); err != nil {
+			return s, err
+		}
+	}
+	if wh.End != "" {
+		if s.end, err = parseClock(wh.End); err != nil {
+			return s, err
+		}
+	}
+	if s.end <= s.start {
+		return s, fmt.Errorf("working hours end %s is not after start %s", wh.End, wh.Start)
+	}
+	if len(wh.Days) > 0 {
+		s.days = map[time.Weekday]bool{}
+		for _, d := range wh.Days {
+			wd, ok := weekdayNames[strings.ToLower(strings.TrimSpace(d))]
+			if !ok {
+				return s, fmt.Errorf("unknown working day %q", d)
+			}
+			s.days[wd] = true
+		}
+	}
+	return s, nil
+}
+
+// accountSchedules resolves the schedule of each account: its entry in
+// account_working_hours by email, then by account type, then the global
+// working hours.
+func accountSchedules(accounts []Account, cfg Config, start, end time.Duration) (map[string]schedule, error) {
+	schedules := map[string]schedule{}
+	for _, a := range accounts {
+		wh, ok := cfg.AccountHours[a.Email]
+		if !ok {
+			wh = cfg.AccountHours[a.Type]
+		}
+		s, err := resolveSchedule(wh, start, end)
+		if err != nil {
+			return nil, fmt.Errorf("working hours for %s: %v", a.Email, err)
+		}
+		schedules[a.Email] = s
+	}
+	return schedules, nil
+}
+
+// minutesOutside counts the minutes of [start, end) that fall outside s,
+// with days taken in loc.
+func minutesOutside(start, end time.Time, s schedule, loc *time.Location) int {
+	inside := time.Duration(0)
+	y, m, d := start.In(loc).Date()
+	for day := time.Date(y, m, d, 0, 0, 0, 0, loc); day.Before(end); day = day.AddDate(0, 0, 1) {
+		if !s.days[day.Weekday()] {
+			continue
+		}
+		winStart, winEnd := day.Add(s.start), day.Add(s.end)
+		if winStart.Before(start) {
+			winStart = start
+		}
+		if winEnd.After(end) {
+			winEnd = end
+		}
+		if winEnd.After(winStart) {
+			inside += winEnd.Sub(winStart)
+		}
+	}
+	return int((end.Sub(start) - inside).Minutes())
+}
+
+// markOutsideHours sets OutsideWorkingHours on each meeting that runs, at
+// least partly, outside its account's working hours, and returns the total
+// out-of-hours meeting minutes.
+func markOutsideHours(events []SimplifiedEvent, schedules map[string]schedule, loc *time.Location) int {
+	total := 0
+	for i, e := range events {
+		s, ok := schedules[e.account]
+		if !ok {
+			continue
+		}
+		start, end, ok := blocksTime(e)
+		if !ok {
+			continue
+		}
+		if minutes := minutesOutside(start, end, s, loc); minutes > 0 {
+			events[i].OutsideWorkingHours = true
+			total += minutes
+		}
+	}
+	return total
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// --- ICS Export ---
+
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+
+// icsFold splits a content line into 75-octet chunks (RFC 5545 3.1) without
+// breaking a UTF-8 sequence.
+func icsFold(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := utf8.RuneLen(r)
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
+
+// icsTime renders a start/end value: UTC date-time for timed events, a DATE
+// value for all-day ones.
+func icsTime(name, value string) (string, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return name + ":" + t.UTC().Format("20060102T150405Z"), true
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return name + ";VALUE=DATE:" + t.Format("20060102"), true
+	}
+	return "", false
+}
+
+// eventUID keeps Google's iCalUID when known so re-imports update instead of
+// duplicating; otherwise it derives a stable one from the event.
+func eventUID(e SimplifiedEvent) string {
+	if e.uid != "" {
+		return e.uid
+	}
+	sum := sha1.Sum([]byte(e.account + "\x00" + e.Summary + "\x00" + e.Start + "\x00" + e.End))
+	return fmt.Sprintf("%x@calendar-brief", sum[:10])
+}
+
+// writeICS prints events as an iCalendar file.
+func writeICS(events []SimplifiedEvent, now time.Time) {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//claude-settings//calendar-brief//EN",
+		"CALSCALE:GREGORIAN",
+	}
+	stamp := "DTSTAMP:" + now.UTC().Format("20060102T150405Z")
+	seen := map[string]bool{}
+	for _, e := range events {
+		start, ok1 := icsTime("DTSTART", e.Start)
+		end, ok2 := icsTime("DTEND", e.End)
+		// An invite shared by two of my accounts shows up twice with one UID.
+		if !ok1 || seen[eventUID(e)+start] {
+			continue
+		}
+		seen[eventUID(e)+start] = true
+		lines = append(lines, "BEGIN:VEVENT", "UID:"+eventUID(e), stamp, start)
+		if ok2 {
+			lines = append(lines, end)
+		}
+		lines = append(lines, "SUMMARY:"+icsTextEscaper.Replace(e.Summary))
+		if e.Location != "" {
+			lines = append(lines, "LOCATION:"+icsTextEscaper.Replace(e.Location))
+		}
+		if e.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+icsTextEscaper.Replace(e.Description))
+		}
+		if e.MeetingURL != "" {
+			lines = append(lines, "URL:"+e.MeetingURL)
+		}
+		switch e.Status {
+		case "confirmed", "tentative", "cancelled":
+			lines = append(lines, "STATUS:"+strings.ToUpper(e.Status))
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(icsFold(line))
+		b.WriteString("\r\n")
+	}
+	os.Stdout.WriteString(b.String())
+}
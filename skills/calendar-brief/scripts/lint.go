@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// --- Lint ---
+
+type LintIssue struct {
+	Rule    string `json:"rule"`
+	Summary string `json:"summary"`
+	Start   string `json:"start"`
+	Message string `json:"message"`
+}
+
+type roomBooking struct {
+	Room    string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+var timeInTextPattern = regexp.MustCompile(`(?i)\b([01]?\d|2[0-3]):[0-5]\d\b|\b(1[0-2]|[1-9])\s?(am|pm)\b|\d{1,2}\s?시`)
+
+func hasVideoLink(event GogEvent) bool {
+	if url, _ := extractMeetingLink(event); url != "" {
+		return true
+	}
+	return event.ConferenceData != nil && len(event.ConferenceData.EntryPoints) > 0
+}
+
+// lintEvent checks a single raw event against the per-event rules. Room
+// double-booking needs every event at once and is handled by lintRooms.
+func lintEvent(event GogEvent, simplified SimplifiedEvent, now time.Time) []LintIssue {
+	var issues []LintIssue
+	issue := func(rule, msg string) {
+		issues = append(issues, LintIssue{Rule: rule, Summary: simplified.Summary, Start: simplified.Start, Message: msg})
+	}
+
+	if simplified.Status == "cancelled" {
+		return nil
+	}
+
+	people, rooms, responded := 0, 0, 0
+	for _, a := range event.Attendees {
+		if a.Resource {
+			rooms++
+			continue
+		}
+		if a.Self {
+			continue
+		}
+		people++
+		if rs := a.ResponseStatus; rs != "" && rs != "needsAction" {
+			responded++
+		}
+	}
+
+	start, err := time.Parse(time.RFC3339, simplified.Start)
+	if err == nil {
+		if people > 0 && rooms == 0 && simplified.Location == "" && !hasVideoLink(event) {
+			issue("missing_video_link", "Meeting with attendees has no room, location, or video link")
+		}
+		if people > 0 && responded == 0 && start.After(now) && start.Sub(now) <= 24*time.Hour {
+			issue("no_responses", fmt.Sprintf("None of %d attendees has responded and it starts within a day", people))
+		}
+	} else if timeInTextPattern.MatchString(simplified.Summary) {
+		issue("all_day_looks_timed", "All-day event mentions a time of day; it should probably be a timed event")
+	}
+	return issues
+}
+
+func collectRoomBookings(event GogEvent, simplified SimplifiedEvent) []roomBooking {
+	if simplified.Status == "cancelled" {
+		return nil
+	}
+	start, err1 := time.Parse(time.RFC3339, simplified.Start)
+	end, err2 := time.Parse(time.RFC3339, simplified.End)
+	if err1 != nil || err2 != nil {
+		return nil
+	}
+
+	var bookings []roomBooking
+	for _, a := range event.Attendees {
+		if !a.Resource || a.ResponseStatus == "declined" {
+			continue
+		}
+		room := a.DisplayName
+		if room == "" {
+			room = a.Email
+		}
+		bookings = append(bookings, roomBooking{Room: room, Summary: simplified.Summary, Start: start, End: end})
+	}
+	return bookings
+}
+
+func lintRooms(bookings []roomBooking) []LintIssue {
+	var issues []LintIssue
+	for i := 0; i < len(bookings); i++ {
+		for j := i + 1; j < len(bookings); j++ {
+			a, b := bookings[i], bookings[j]
+			if !strings.EqualFold(a.Room, b.Room) {
+				continue
+			}
+			// The same meeting seen from two accounts is not a double booking.
+			if a.Summary == b.Summary && a.Start.Equal(b.Start) {
+				continue
+			}
+			if a.Start.Before(b.End) && b.Start.Before(a.End) {
+				issues = append(issues, LintIssue{
+					Rule:    "double_booked_room",
+					Summary: a.Summary,
+					Start:   a.Start.Format(time.RFC3339),
+					Message: fmt.Sprintf("%s is also booked for %q at the same time", a.Room, b.Summary),
+				})
+			}
+		}
+	}
+	return issues
+}
@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,19 +22,93 @@ type Account struct {
 }
 
 type SimplifiedEvent struct {
-	Summary     string `json:"summary"`
-	Start       string `json:"start"`
-	End         string `json:"end"`
-	Location    string `json:"location"`
-	Status      string `json:"status"`
-	Response    string `json:"response"`
-	AccountType string `json:"account_type"`
+	Type        string   `json:"type,omitempty"` // "task" for --tasks entries; unset for events
+	EventID     string   `json:"event_id,omitempty"`
+	HTMLLink    string   `json:"html_link,omitempty"`
+	Summary     string   `json:"summary"`
+	Start       string   `json:"start"`
+	End         string   `json:"end"`
+	Location    string   `json:"location"`
+	Room        *Room    `json:"room,omitempty"` // the first room booked, see extractRooms
+	Description string   `json:"description,omitempty"`
+	Status      string   `json:"status"`
+	EventType   string   `json:"event_type"` // default, outOfOffice, focusTime or workingLocation
+	Response    string   `json:"response"`
+	Optional    bool     `json:"optional,omitempty"` // I am an optional attendee
+	AccountType string   `json:"account_type"`
+	Accounts    []string `json:"accounts,omitempty"` // every account that sees the event, see dedupeEvents
+	HasConflict bool     `json:"has_conflict"`
+	BackToBack  bool     `json:"back_to_back"`
+	AllDay      bool     `json:"all_day"`
+
+	OutsideWorkingHours bool `json:"outside_working_hours"`
+	NoAgenda            bool `json:"no_agenda,omitempty"` // see lacksAgenda
+
+	Tags []string `json:"tags,omitempty"` // from --tag, see annotate
+	Note string   `json:"note,omitempty"`
+
+	TravelWarning string `json:"travel_warning,omitempty"`
+
+	Reminders        []Reminder `json:"reminders,omitempty"`         // the event's own, see extractReminders
+	RemindersDefault bool       `json:"reminders_default,omitempty"` // the calendar's default reminders apply
+	NoReminder       bool       `json:"no_reminder,omitempty"`       // a meeting I attend that will not remind me
+
+	DurationMinutes int  `json:"duration_minutes"`
+	StartsInMinutes *int `json:"starts_in_minutes,omitempty"` // today's brief only; negative once started
+
+	MeetingURL      string `json:"meeting_url,omitempty"`
+	MeetingProvider string `json:"meeting_provider,omitempty"`
+
+	OrganizerEmail string `json:"organizer_email,omitempty"`
+	OrganizerName  string `json:"organizer_name,omitempty"`
+	IAmOrganizer   bool   `json:"i_am_organizer"`
+
+	AttendeeCount int        `json:"attendee_count,omitempty"`
+	Size          string     `json:"size,omitempty"` // one_on_one, small or large, see meetingSize
+	Attendees     []Attendee `json:"attendees,omitempty"`
+
+	Recordings []RecordingLink `json:"recordings,omitempty"`
+
+	OnCall        bool `json:"on_call,omitempty"`
+	OnCallHandoff bool `json:"on_call_handoff,omitempty"`
+
+	uid         string // iCalUID, for --format ics
+	account     string // owning account email, for --group-by
+	calendar    string // source calendar ID, for --group-by
+	outOfOffice bool
+	private     bool // see isPrivate, for --redact
+
+	colorID        string // for --track-by color
+	attendeeDomain string // for --track-by domain
+
+	attending int  // invitees who have not declined, for --cost
+	recurring bool // an instance of a recurring series
 }
 
 type Output struct {
-	Accounts []Account         `json:"accounts"`
-	Events   []SimplifiedEvent `json:"events"`
-	Errors   []AccountError    `json:"errors,omitempty"`
+	Timezone          string              `json:"timezone,omitempty"`
+	Accounts          []Account           `json:"accounts"`
+	Events            []SimplifiedEvent   `json:"events"`
+	AllDayEvents      []SimplifiedEvent   `json:"all_day_events,omitempty"`
+	Conflicts         []Conflict          `json:"conflicts"`
+	FreeSlots         []FreeSlot          `json:"free_slots,omitempty"`
+	DaysOff           []DayOff            `json:"days_off,omitempty"`
+	TeammatesOOO      []TeammateOOO       `json:"teammates_ooo,omitempty"`
+	OnSite            []OnSiteDay         `json:"on_site,omitempty"` // days with a meeting in a booked room
+	Alerts            []Alert             `json:"alerts,omitempty"`
+	Changes           []EventChange       `json:"changes,omitempty"`
+	DiffSince         string              `json:"diff_since,omitempty"` // when the --diff baseline was saved
+	Stats             *WeekStats          `json:"stats,omitempty"`
+	Day               *DaySpan            `json:"day,omitempty"` // single-day briefs only
+	Timeline          []TimelineDay       `json:"timeline,omitempty"`
+	Declined          []DeclinedEvent     `json:"declined,omitempty"` // see findDeclined
+	SuggestedDeclines []DeclineSuggestion `json:"suggested_declines,omitempty"`
+	Person            *PersonMeetings     `json:"person,omitempty"`
+	Prep              []PrepBlock         `json:"prep,omitempty"`
+	Lint              []LintIssue         `json:"lint,omitempty"`
+	Errors            []AccountError      `json:"errors,omitempty"`
+	Truncated         bool                `json:"truncated"` // some account had more events than --max
+	Meta              *Meta               `json:"meta,omitempty"`
 }
 
 type AccountError struct {
@@ -42,16 +119,16 @@ type AccountError struct {
 // --- Account Discovery & Classification ---
 
 var personalDomains = map[string]bool{
-	"gmail.com":    true,
-	"naver.com":    true,
-	"daum.net":     true,
-	"hanmail.net":  true,
-	"yahoo.com":    true,
-	"hotmail.com":  true,
-	"outlook.com":  true,
-	"icloud.com":   true,
-	"kakao.com":    true,
-	"nate.com":     true,
+	"gmail.com":   true,
+	"naver.com":   true,
+	"daum.net":    true,
+	"hanmail.net": true,
+	"yahoo.com":   true,
+	"hotmail.com": true,
+	"outlook.com": true,
+	"icloud.com":  true,
+	"kakao.com":   true,
+	"nate.com":    true,
 }
 
 func discoverAccounts() []string {
@@ -92,7 +169,7 @@ func classifyAccount(email string) string {
 	return "work"
 }
 
-func resolveAccounts(personal, work string) []Account {
+func resolveAccounts(personal, work, classifyCommand string) ([]Account, error) {
 	var accounts []Account
 	if personal != "" {
 		accounts = append(accounts, Account{Email: personal, Type: "personal"})
@@ -101,36 +178,24 @@ func resolveAccounts(personal, work string) []Account {
 		accounts = append(accounts, Account{Email: work, Type: "work"})
 	}
 	if len(accounts) > 0 {
-		return accounts
+		return accounts, nil
 	}
-	for _, email := range discoverAccounts() {
-		accounts = append(accounts, Account{Email: email, Type: classifyAccount(email)})
-	}
-	return accounts
+	return classifyAccounts(discoverAccounts(), classifyCommand)
 }
 
 // --- Date Args ---
 
-func buildGogArgs(today, tomorrow, thisWeek, nextWeek bool) []string {
+func buildGogArgs(now time.Time, today, tomorrow, thisWeek, nextWeek bool, weekStart time.Weekday) []string {
 	// Priority: next-week > this-week > tomorrow > today
 	if nextWeek {
-		now := time.Now()
-		weekday := now.Weekday() // Sunday=0, Monday=1 ...
-		// Convert to Python convention: Mon=0
-		pyWeekday := (int(weekday) + 6) % 7
-		daysUntilMonday := (7 - pyWeekday) % 7
-		if daysUntilMonday == 0 {
-			daysUntilMonday = 7
-		}
-		nextMonday := now.AddDate(0, 0, daysUntilMonday)
-		nextSunday := nextMonday.AddDate(0, 0, 6)
+		first := startOfWeek(now, weekStart).AddDate(0, 0, 7)
 		return []string{
-			"--from", nextMonday.Format("2006-01-02"),
-			"--to", nextSunday.Format("2006-01-02"),
+			"--from", first.Format("2006-01-02"),
+			"--to", first.AddDate(0, 0, 6).Format("2006-01-02"),
 		}
 	}
 	if thisWeek {
-		return []string{"--week", "--week-start=mon"}
+		return []string{"--week", "--week-start=" + weekStartName(weekStart)}
 	}
 	if tomorrow {
 		return []string{"--tomorrow"}
@@ -138,143 +203,350 @@ func buildGogArgs(today, tomorrow, thisWeek, nextWeek bool) []string {
 	return []string{"--today"}
 }
 
-// --- Event Fetching ---
+// dateWindow returns the [from, to) interval covered by the selected date flag,
+// for sources that take absolute times instead of gog date args. Day
+// boundaries follow now's location.
+func dateWindow(now time.Time, today, tomorrow, thisWeek, nextWeek bool, weekStart time.Weekday) (time.Time, time.Time) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first := startOfWeek(now, weekStart)
 
-func fetchEvents(accountEmail string, gogDateArgs []string) ([]map[string]interface{}, error) {
-	args := []string{"calendar", "events", "primary", "--json", "--max=50", fmt.Sprintf("--account=%s", accountEmail)}
-	args = append(args, gogDateArgs...)
+	if nextWeek {
+		return first.AddDate(0, 0, 7), first.AddDate(0, 0, 14)
+	}
+	if thisWeek {
+		return first, first.AddDate(0, 0, 7)
+	}
+	if tomorrow {
+		return midnight.AddDate(0, 0, 1), midnight.AddDate(0, 0, 2)
+	}
+	return midnight, midnight.AddDate(0, 0, 1)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// windowGogArgs pins gog to an explicit interval, so day boundaries come from
+// our timezone rather than gog's.
+func windowGogArgs(from, to time.Time) []string {
+	return []string{
+		"--from", from.Format(time.RFC3339),
+		"--to", to.Format(time.RFC3339),
+	}
+}
 
-	cmd := exec.CommandContext(ctx, "gog", args...)
-	var stderr strings.Builder
-	cmd.Stderr = &stderr
-	out, err := cmd.Output()
-	if err != nil {
-		errMsg := strings.TrimSpace(stderr.String())
-		if errMsg == "" {
-			errMsg = fmt.Sprintf("gog exited with code %d", cmd.ProcessState.ExitCode())
+// dateOptions holds the date-selection flags. Next and Upcoming win over
+// everything, then FreeAt, then an explicit Date or From/To range, then NextBusinessDay, then Weekend, then
+// Month/NextMonth, then a When phrase, then the relative modes.
+type dateOptions struct {
+	Today, Tomorrow, ThisWeek, NextWeek bool
+	Date                                string // YYYY-MM-DD, a single day
+	From, To                            string // YYYY-MM-DD, both inclusive
+	When                                string // e.g. "next monday", "in 3 days"
+	NextBusinessDay                     bool
+	Weekend                             bool
+	Month, NextMonth                    bool
+	Holidays                            map[string]bool // skipped by NextBusinessDay
+	Next                                int             // upcoming events, see nextHorizon
+	Upcoming                            bool            // now through nextHorizon, for --person
+	WeekStart                           time.Weekday    // first day of ThisWeek, NextWeek and week phrases in When
+	FreeAt                              string          // "YYYY-MM-DD HH:MM"; its day and freeAtSearchDays more
+	Weeks                               int             // this week and the ones after it, whole weeks
+}
+
+// dateRange is a resolved selection: the [From, To) window and the gog args
+// that fetch it.
+type dateRange struct {
+	From, To time.Time
+	GogArgs  []string
+}
+
+var dateLayouts = []string{"2006-01-02", "2006-1-2", "2006/01/02", "2006/1/2", "2006.01.02", "20060102"}
+
+// parseDate accepts a calendar date in a few common spellings and returns
+// midnight of that day in loc.
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), loc); err == nil {
+			return t, nil
 		}
-		return nil, fmt.Errorf("%s", errMsg)
 	}
+	return time.Time{}, fmt.Errorf("invalid date %q (want YYYY-MM-DD)", s)
+}
 
-	// Try as object with "events" key first
-	var asMap map[string]interface{}
-	if err := json.Unmarshal(out, &asMap); err == nil {
-		if eventsRaw, ok := asMap["events"]; ok {
-			if eventsSlice, ok := eventsRaw.([]interface{}); ok {
-				return toMapSlice(eventsSlice), nil
+// spanRange covers the whole days from first through last.
+func spanRange(first, last time.Time, pinned bool) dateRange {
+	r := dateRange{From: first, To: last.AddDate(0, 0, 1)}
+	r.GogArgs = []string{"--from", first.Format("2006-01-02"), "--to", last.Format("2006-01-02")}
+	if pinned {
+		r.GogArgs = windowGogArgs(r.From, r.To)
+	}
+	return r
+}
+
+// resolveDateRange turns the date flags into a window. When pinned is set
+// (an explicit timezone, or $BRIEF_NOW), gog gets exact RFC 3339 bounds
+// instead of its relative shortcuts so day boundaries follow that timezone
+// and that day.
+func resolveDateRange(now time.Time, opts dateOptions, pinned bool) (dateRange, error) {
+	if opts.Next > 0 || opts.Upcoming {
+		// Starts mid-day, so gog always gets exact bounds.
+		to := now.Add(nextHorizon)
+		return dateRange{From: now, To: to, GogArgs: windowGogArgs(now, to)}, nil
+	}
+	if opts.FreeAt != "" {
+		start, err := parseDateTime(opts.FreeAt, now.Location())
+		if err != nil {
+			return dateRange{}, fmt.Errorf("--free-at: %v", err)
+		}
+		day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, now.Location())
+		return spanRange(day, day.AddDate(0, 0, freeAtSearchDays), pinned), nil
+	}
+	if opts.Date != "" {
+		if opts.From != "" || opts.To != "" {
+			return dateRange{}, fmt.Errorf("--date cannot be combined with --from/--to")
+		}
+		day, err := parseDate(opts.Date, now.Location())
+		if err != nil {
+			return dateRange{}, err
+		}
+		return spanRange(day, day, pinned), nil
+	}
+	if opts.From != "" || opts.To != "" {
+		if opts.From == "" {
+			return dateRange{}, fmt.Errorf("--to requires --from")
+		}
+		from, err := parseDate(opts.From, now.Location())
+		if err != nil {
+			return dateRange{}, err
+		}
+		last := from
+		if opts.To != "" {
+			if last, err = parseDate(opts.To, now.Location()); err != nil {
+				return dateRange{}, err
 			}
 		}
-		// dict but no "events" key — wrap it? Python returns data itself via data.get("events", data)
-		// which means if "events" key missing, return the dict as-is treated as "data"
-		// But since data is a dict not a list, this won't iterate well.
-		// Match Python: data.get("events", data) where data is dict → returns dict
-		// then `for e in raw_events` iterates over dict keys, which is unusual.
-		// Practically, gog returns {"events": [...]} so this edge case is unlikely.
-		// Return empty for safety.
-		return nil, nil
+		if last.Before(from) {
+			return dateRange{}, fmt.Errorf("--to %s is before --from %s", last.Format("2006-01-02"), from.Format("2006-01-02"))
+		}
+		return spanRange(from, last, pinned), nil
+	}
+	if opts.NextBusinessDay {
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		day := addBusinessDays(midnight, 1, opts.Holidays)
+		return spanRange(day, day, pinned), nil
+	}
+	if opts.Weekend {
+		// The coming Saturday and Sunday; on a weekend, the current one.
+		midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		saturday := midnight.AddDate(0, 0, (int(time.Saturday)-int(now.Weekday())+7)%7)
+		if now.Weekday() == time.Sunday {
+			saturday = midnight.AddDate(0, 0, -1)
+		}
+		return spanRange(saturday, saturday.AddDate(0, 0, 1), pinned), nil
+	}
+	if opts.Month || opts.NextMonth {
+		// Step from the 1st so short months never overflow (Jan 31 + 1 month).
+		first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		if opts.NextMonth {
+			first = first.AddDate(0, 1, 0)
+		}
+		return spanRange(first, first.AddDate(0, 1, -1), pinned), nil
+	}
+	if opts.Weeks > 0 {
+		first := startOfWeek(now, opts.WeekStart)
+		return spanRange(first, first.AddDate(0, 0, 7*opts.Weeks-1), pinned), nil
+	}
+	if opts.When != "" {
+		from, last, err := parseWhen(opts.When, now, opts.WeekStart)
+		if err != nil {
+			return dateRange{}, err
+		}
+		return spanRange(from, last, pinned), nil
 	}
 
-	// Try as array
-	var asSlice []interface{}
-	if err := json.Unmarshal(out, &asSlice); err == nil {
-		return toMapSlice(asSlice), nil
+	from, to := dateWindow(now, opts.Today, opts.Tomorrow, opts.ThisWeek, opts.NextWeek, opts.WeekStart)
+	r := dateRange{From: from, To: to, GogArgs: buildGogArgs(now, opts.Today, opts.Tomorrow, opts.ThisWeek, opts.NextWeek, opts.WeekStart)}
+	if pinned {
+		r.GogArgs = windowGogArgs(from, to)
 	}
+	return r, nil
+}
+
+// --- Event Fetching ---
 
-	return nil, fmt.Errorf("unexpected JSON format from gog")
+func fetchEvents(ctx context.Context, accountEmail string, gogDateArgs []string, max int) (gogList[GogEvent], bool, error) {
+	return fetchCalendarEvents(ctx, "primary", accountEmail, gogDateArgs, max)
 }
 
-func toMapSlice(raw []interface{}) []map[string]interface{} {
-	result := make([]map[string]interface{}, 0, len(raw))
-	for _, item := range raw {
-		if m, ok := item.(map[string]interface{}); ok {
-			result = append(result, m)
+// eventPageSize is how many events to ask gog for per page.
+const eventPageSize = 250
+
+// fetchCalendarEvents reads up to max events, following gog's nextPageToken
+// from page to page. truncated reports that more events were left: either
+// a next page remained at max, or gog returned a full page without a token,
+// so there is no telling whether it stopped early. The pages are merged
+// into one list, Malformed counts included.
+func fetchCalendarEvents(ctx context.Context, calendarID, accountEmail string, gogDateArgs []string, max int) (gogList[GogEvent], bool, error) {
+	var all gogList[GogEvent]
+	page := ""
+	for {
+		want := min(max-len(all.Items), eventPageSize)
+		list, err := fetchEventsPage(ctx, calendarID, accountEmail, gogDateArgs, want, page)
+		if err != nil {
+			return gogList[GogEvent]{}, false, err
+		}
+		all.Items = append(all.Items, list.Items...)
+		all.Malformed += list.Malformed
+		if list.NextPageToken == "" {
+			return all, len(list.Items) >= want, nil
 		}
+		if len(all.Items) >= max {
+			return all, true, nil
+		}
+		page = list.NextPageToken
 	}
-	return result
 }
 
-// --- Event Processing ---
-
-func extractMyResponse(event map[string]interface{}) string {
-	attendeesRaw, ok := event["attendees"]
-	if !ok {
-		return ""
-	}
-	attendees, ok := attendeesRaw.([]interface{})
-	if !ok {
-		return ""
+func fetchEventsPage(ctx context.Context, calendarID, accountEmail string, gogDateArgs []string, max int, page string) (gogList[GogEvent], error) {
+	args := []string{"calendar", "events", calendarID, "--json", fmt.Sprintf("--max=%d", max), fmt.Sprintf("--account=%s", accountEmail)}
+	if page != "" {
+		args = append(args, fmt.Sprintf("--page=%s", page))
 	}
-	for _, aRaw := range attendees {
-		a, ok := aRaw.(map[string]interface{})
-		if !ok {
-			continue
-		}
-		if selfVal, ok := a["self"]; ok {
-			if isSelf, ok := selfVal.(bool); ok && isSelf {
-				if rs, ok := a["responseStatus"].(string); ok {
-					return rs
-				}
-				return ""
-			}
-		}
+	args = append(args, gogDateArgs...)
+
+	var list gogList[GogEvent]
+	err := streamGog(ctx, func(r io.Reader) error {
+		var err error
+		list, err = decodeList[GogEvent](r, "events")
+		return err
+	}, args...)
+	if err != nil {
+		return gogList[GogEvent]{}, err
 	}
-	return ""
+	return list, nil
+}
+
+type fetchResult struct {
+	events    []GogEvent
+	truncated bool
+	meta      AccountMeta
+	err       error
 }
 
-func getString(m map[string]interface{}, key string) string {
-	if v, ok := m[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
+// fetchAllEvents fetches every account at once, each as its own job (see
+// runAccountJob); gogPool bounds the gog processes actually running. Results
+// are indexed like accounts so output order does not depend on which account
+// answers first.
+func fetchAllEvents(accounts []Account, gogDateArgs []string, max int, opts jobOptions, health map[string]accountHealth) []fetchResult {
+	results := make([]fetchResult, len(accounts))
+	parallel(len(accounts), func(i int) {
+		email := accounts[i].Email
+		var list gogList[GogEvent]
+		var truncated bool
+		meta, err := runAccountJob(email, optionsFor(opts, health[email]), func(ctx context.Context) error {
+			var err error
+			list, truncated, err = fetchEvents(ctx, email, gogDateArgs, max)
+			return err
+		})
+		events := list.Items
+		meta.Truncated, meta.Malformed = truncated, list.Malformed
+		meta.Degraded = health[email].Failures >= degradeAfter
+		results[i] = fetchResult{events: events, truncated: truncated, meta: meta, err: err}
+	})
+	return results
+}
+
+// --- Event Processing ---
+
+func extractMyResponse(event GogEvent) string {
+	for _, a := range event.Attendees {
+		if a.Self {
+			return a.ResponseStatus
 		}
 	}
 	return ""
 }
 
-func getMap(m map[string]interface{}, key string) map[string]interface{} {
-	if v, ok := m[key]; ok {
-		if sub, ok := v.(map[string]interface{}); ok {
-			return sub
+// iAmOptional reports whether the organizer marked me optional.
+func iAmOptional(event GogEvent) bool {
+	for _, a := range event.Attendees {
+		if a.Self {
+			return a.Optional
 		}
 	}
-	return nil
+	return false
 }
 
-func simplifyEvent(event map[string]interface{}, accountType string) SimplifiedEvent {
-	summary := getString(event, "summary")
+func simplifyEvent(event GogEvent, accountType string) SimplifiedEvent {
+	summary := event.Summary
 	if summary == "" {
 		summary = "(No title)"
 	}
+	startStr := event.Start.value()
+	endStr := event.End.value()
 
-	startMap := getMap(event, "start")
-	endMap := getMap(event, "end")
-
-	startStr := ""
-	if startMap != nil {
-		startStr = getString(startMap, "dateTime")
-		if startStr == "" {
-			startStr = getString(startMap, "date")
-		}
+	eventType := event.EventType
+	if eventType == "" {
+		eventType = "default"
 	}
-
-	endStr := ""
-	if endMap != nil {
-		endStr = getString(endMap, "dateTime")
-		if endStr == "" {
-			endStr = getString(endMap, "date")
+	meetingURL, provider := extractMeetingLink(event)
+	attendees := extractAttendees(event)
+	var room *Room
+	if rooms := extractRooms(event); len(rooms) > 0 {
+		room = &rooms[0]
+	}
+	reminders, remindersDefault, noReminder := extractReminders(event)
+	attending := 0
+	for _, a := range attendees {
+		if a.Response != "declined" {
+			attending++
 		}
 	}
 
+	allDay := startStr != "" && !strings.Contains(startStr, "T")
+	response := extractMyResponse(event)
+
 	return SimplifiedEvent{
-		Summary:     summary,
-		Start:       startStr,
-		End:         endStr,
-		Location:    getString(event, "location"),
-		Status:      getString(event, "status"),
-		Response:    extractMyResponse(event),
-		AccountType: accountType,
+		EventID:          event.ID,
+		HTMLLink:         event.HTMLLink,
+		Summary:          summary,
+		Start:            startStr,
+		End:              endStr,
+		AllDay:           allDay,
+		DurationMinutes:  durationMinutes(startStr, endStr),
+		Location:         event.Location,
+		Room:             room,
+		Status:           event.Status,
+		EventType:        eventType,
+		Response:         response,
+		Optional:         iAmOptional(event),
+		Reminders:        reminders,
+		RemindersDefault: remindersDefault,
+		NoReminder:       noReminder && !allDay && response != "declined" && event.Status != "cancelled",
+		AccountType:      accountType,
+		MeetingURL:       meetingURL,
+		MeetingProvider:  provider,
+		OrganizerEmail:   event.Organizer.Email,
+		OrganizerName:    event.Organizer.DisplayName,
+		IAmOrganizer:     event.Organizer.Self,
+		AttendeeCount:    len(attendees),
+		Size:             meetingSize(len(attendees)),
+		Attendees:        attendees,
+		outOfOffice:      isOutOfOffice(event, summary),
+		private:          isPrivate(event),
+		uid:              event.ICalUID,
+		colorID:          event.ColorID,
+		attendeeDomain:   attendeeDomain(attendees),
+		attending:        attending,
+		recurring:        event.RecurringEventID != "",
+	}
+}
+
+// convertEventTimes rewrites timed start/end values into loc. All-day dates
+// have no time of day and are left as-is.
+func convertEventTimes(e *SimplifiedEvent, loc *time.Location) {
+	if t, err := time.Parse(time.RFC3339, e.Start); err == nil {
+		e.Start = t.In(loc).Format(time.RFC3339)
+	}
+	if t, err := time.Parse(time.RFC3339, e.End); err == nil {
+		e.End = t.In(loc).Format(time.RFC3339)
 	}
 }
 
@@ -285,40 +557,509 @@ func main() {
 	work := flag.String("work", "", "Work account email")
 	today := flag.Bool("today", false, "Today's events (default)")
 	tomorrow := flag.Bool("tomorrow", false, "Tomorrow's events")
-	thisWeek := flag.Bool("this-week", false, "This week (Mon-Sun)")
-	nextWeek := flag.Bool("next-week", false, "Next week (Mon-Sun)")
+	thisWeek := flag.Bool("this-week", false, "This week (Mon-Sun, or Sun-Sat with --week-start=sun)")
+	nextWeek := flag.Bool("next-week", false, "Next week (Mon-Sun, or Sun-Sat with --week-start=sun)")
+	weeks := flag.Int("weeks", 0, "This week and the N-1 after it, grouped by ISO week (implies --group-by=week)")
+	weekStartFlag := flag.String("week-start", "", "First day of the week: mon or sun (default: config week_start, then $"+weekStartEnv+", then mon)")
+	month := flag.Bool("month", false, "This calendar month")
+	nextMonth := flag.Bool("next-month", false, "Next calendar month")
+	next := flag.Int("next", 0, "The next N upcoming timed events across accounts, looking up to 14 days ahead")
+	nextBusinessDay := flag.Bool("next-business-day", false, "Next weekday that is not a configured holiday")
+	weekend := flag.Bool("weekend", false, "The coming Saturday and Sunday (this weekend's, on a weekend)")
+	date := flag.String("date", "", "Specific date (YYYY-MM-DD)")
+	when := flag.String("when", "", `Natural-language date ("next monday", "in 3 days", "friday", "내일")`)
+	fromDate := flag.String("from", "", "Start date YYYY-MM-DD (inclusive)")
+	toDate := flag.String("to", "", "End date YYYY-MM-DD (inclusive, defaults to --from)")
+	tz := flag.String("tz", "", "IANA timezone for event times and day boundaries (e.g. Asia/Seoul)")
+	configPath := flag.String("config", defaultConfigPath(), "Path to config.json")
+	free := flag.Bool("free", false, "Report free slots within working hours across all accounts")
+	workHours := flag.String("work-hours", "", "Working hours for --free as HH:MM-HH:MM (default 09:00-18:00)")
+	timeline := flag.Bool("timeline", false, "Add a per-day list of busy and free blocks, merged across accounts")
+	suggestDeclinesFlag := flag.Bool("suggest-declines", false, fmt.Sprintf("Suggest recurring meetings to drop, from my responses over the last %d days", declineHistoryDays))
+	minGap := flag.Int("min-gap", 0, "Shortest free slot to report, in minutes (default 30)")
+	agendaMinutes := flag.Int("agenda-minutes", defaultAgendaMinutes, "Flag meetings longer than this with no description or attachment as no_agenda")
+	travelBuffer := flag.Int("travel-buffer", 0, "Minutes needed between meetings at different places (default 15)")
+	maxEvents := flag.Int("max", 500, "Most events to read per account; more are reported as truncated")
+	concurrency := flag.Int("concurrency", defaultConcurrency, "Max gog processes in flight across accounts, calendars and pages")
+	accountTimeout := flag.Duration("account-timeout", 60*time.Second, "Time budget per account, retries included")
+	retries := flag.Int("retries", 1, "Retries per account after a failed fetch")
+	onCall := flag.Bool("on-call", false, "Merge on-call shifts from PagerDuty/Opsgenie")
+	prep := flag.Bool("prep", false, "Report meetings missing a prep block")
+	apply := flag.Bool("apply", false, "With --prep, create the missing prep events")
+	lint := flag.Bool("lint", false, "Flag malformed or risky events")
+	person := flag.String("person", "", "Only meetings with this email, plus the files attached to them (upcoming meetings unless a date flag is given)")
+	freeAt := flag.String("free-at", "", `Output only whether this slot ("YYYY-MM-DD HH:MM") is free on every account, with conflicts and nearest alternatives`)
+	slotMinutes := flag.Int("duration", 30, "Length of the --free-at slot or --create event, in minutes")
+	create := flag.Bool("create", false, "Create an event from --title, --start and --end/--duration, and output it")
+	title := flag.String("title", "", "With --create, the event title")
+	startAt := flag.String("start", "", `With --create, the start ("YYYY-MM-DD HH:MM")`)
+	endAt := flag.String("end", "", `With --create, the end ("YYYY-MM-DD HH:MM", or HH:MM on the start day); default --duration after --start`)
+	account := flag.String("account", "", "With --create, the account whose calendar gets the event (default: the only account)")
+	location := flag.String("location", "", "With --create, the event location")
+	invite := flag.String("invite", "", "With --create, comma-separated emails to invite")
+	doubleBooked := flag.Bool("double-booked", false, "Output only the time ranges where two or more accepted events overlap")
+	hideDeclined := flag.Bool("hide-declined", true, "Drop events I declined (--hide-declined=false to keep them)")
+	hideCancelled := flag.Bool("hide-cancelled", false, "Drop cancelled events")
+	hideFocusTime := flag.Bool("hide-focus-time", false, "Drop focus-time blocks")
+	tasks := flag.Bool("tasks", false, "Add open Google Tasks due in the range as all-day entries with type \"task\"")
+	hideOptional := flag.Bool("hide-optional", false, "Drop meetings I am only an optional attendee of")
+	redact := flag.Bool("redact", false, "Show private events as \"Busy\" and drop attendee emails, for sharing the brief")
+	onlyOneOnOne := flag.Bool("only-1on1", false, "Keep only one-on-one meetings (same as --size=one_on_one)")
+	sizeSpec := flag.String("size", "", "Keep only meetings of these sizes: comma-separated one_on_one, small, large")
+	match := flag.String("match", "", "Keep only events whose summary, description or location match this text or regex")
+	exclude := flag.String("exclude", "", "Drop events whose summary, description or location match this text or regex")
+	withAttendees := flag.Bool("attendees", false, "Include the attendee list on each event")
+	withIDs := flag.Bool("ids", true, "Include event_id and html_link on each event (--ids=false to drop them)")
+	alerts := flag.Bool("alerts", false, "Report changes since the last --alerts run that match the config's alert_rules")
+	diff := flag.Bool("diff", false, "Report events added, moved or cancelled since the last --diff run for the same range")
+	format := flag.String("format", "json", "Output format: json, ics, csv (time-tracking entries for meetings that have ended) or speech (a paragraph to read aloud)")
+	chartPath := flag.String("chart", "", "With --this-week/--next-week, write an SVG chart of meeting hours per day to this path")
+	cost := flag.Bool("cost", false, "With --this-week/--next-week, add meeting cost (attendees × duration) to stats")
+	compare := flag.Bool("compare", false, "With --this-week/--next-week, compare stats with the week before")
+	trackBy := flag.String("track-by", "category", "Group --format csv entries by category ([Client] title prefix), color or domain (attendees')")
+	var description descriptionFlag
+	flag.Var(&description, "description", "Include descriptions, HTML stripped, cut to N characters (--description=N, default 280)")
+	recordings := flag.Bool("recordings", false, "Attach recording/transcript/notes mail to meetings that have ended")
+	splitAll := flag.Bool("split-all-day", false, "Emit all-day events separately as all_day_events")
+	groupBy := flag.String("group-by", "", "Group events into sections by day, week (ISO), account or calendar")
+	fieldSpec := flag.String("fields", "", "Comma-separated event fields to emit (e.g. summary,start)")
+	templatePath := flag.String("template", "", "Render the output through a text/template file instead of JSON")
+	locale := flag.String("locale", "", "Language for --template output and --format speech: en or ko (default: locale in config.json, then en)")
+	audit := flag.Bool("audit", false, "List the audit log of calendar changes")
+	undo := flag.String("undo", "", "Reverse a logged action: LAST or an audit entry ID")
+	accept := flag.String("accept", "", "Accept the invitation with this event ID")
+	decline := flag.String("decline", "", "Decline the invitation with this event ID")
+	tentative := flag.String("tentative", "", "Answer maybe to the invitation with this event ID")
+	requireApproval := flag.Bool("require-approval", false, "With --prep --apply or an RSVP, stage the change for --approve instead of making it")
+	pending := flag.Bool("pending", false, "List actions staged by --require-approval")
+	approve := flag.String("approve", "", "Perform staged actions: ALL or a pending action ID")
+	tag := flag.String("tag", "", "Annotate an event for later briefs: EVENT_ID:tag[,tag...] (a recurring series ID covers every instance)")
+	note := flag.String("note", "", "With --tag, attach this note to the event")
+	untag := flag.String("untag", "", "Remove tags: EVENT_ID:tag[,tag...], or EVENT_ID to drop its tags and note")
+	listTags := flag.Bool("tags", false, "List event annotations made with --tag")
+	exportBundle := flag.String("export-bundle", "", "Write the config, accounts, tags and notes of both brief tools to this encrypted file (passphrase from $"+bundlePassphraseEnv+")")
+	importBundle := flag.String("import-bundle", "", "Restore the files of a bundle made with --export-bundle, replacing existing ones")
+	includeState := flag.Bool("include-state", false, "With --export-bundle, add the rest of the local state (pending actions, audit log, snapshots)")
+	readOnly := flag.Bool("read-only", readOnlyFromEnv(), "Refuse every write action (also set by BRIEF_READ_ONLY=1)")
+	pprofSpec := flag.String("pprof", "", "Profile the run: a directory for cpu.pprof and heap.pprof, or host:port to serve net/http/pprof")
 	flag.Parse()
+	gogPool = newWorkerPool(*concurrency)
 
-	// Default to today when no date flag is given
-	if !*today && !*tomorrow && !*thisWeek && !*nextWeek {
-		*today = true
+	if *pprofSpec != "" {
+		stop, err := startProfiling(*pprofSpec)
+		if err != nil {
+			exitWithError(fmt.Sprintf("--pprof: %v", err))
+		}
+		defer stop()
 	}
 
-	accounts := resolveAccounts(*personal, *work)
-	if len(accounts) == 0 {
-		errObj := map[string]string{
-			"error": "No accounts found. Use --personal/--work or configure gog auth.",
+	if *readOnly {
+		var refused []string
+		if *apply && !*requireApproval {
+			refused = append(refused, "--apply")
+		}
+		if *create && !*requireApproval {
+			refused = append(refused, "--create")
+		}
+		if (*accept != "" || *decline != "" || *tentative != "") && !*requireApproval {
+			refused = append(refused, "--accept/--decline/--tentative")
+		}
+		if *undo != "" {
+			refused = append(refused, "--undo")
+		}
+		if *approve != "" {
+			refused = append(refused, "--approve")
+		}
+		if len(refused) > 0 {
+			exitWithError(fmt.Sprintf("Read-only mode refuses %s", strings.Join(refused, ", ")))
+		}
+	}
+
+	if *audit {
+		writeJSON(runAuditList())
+		return
+	}
+	if *undo != "" {
+		writeJSON(runUndo(*undo))
+		return
+	}
+	if *pending {
+		writeJSON(PendingOutput{Pending: loadPending()})
+		return
+	}
+	if *tag != "" {
+		writeJSON(runTag(*tag, *note, time.Now()))
+		return
+	}
+	if *untag != "" {
+		writeJSON(runUntag(*untag))
+		return
+	}
+	if *listTags {
+		writeJSON(AnnotationsOutput{Annotations: loadAnnotations()})
+		return
+	}
+	if *approve != "" {
+		writeJSON(runApprove(*approve))
+		return
+	}
+	if *exportBundle != "" || *importBundle != "" {
+		var output BundleOutput
+		var err error
+		if *exportBundle != "" {
+			output, err = runExportBundle(*exportBundle, *includeState, time.Now())
+		} else {
+			output, err = runImportBundle(*importBundle)
+		}
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		writeJSON(output)
+		return
+	}
+
+	// Default to today (upcoming meetings for --person) when no date flag is given
+	upcoming := false
+	if !*today && !*tomorrow && !*thisWeek && !*nextWeek && *date == "" && *fromDate == "" && *toDate == "" && *when == "" && !*nextBusinessDay && !*weekend && !*month && !*nextMonth && *next <= 0 && *freeAt == "" && *weeks == 0 {
+		if *person != "" {
+			upcoming = true
+		} else {
+			*today = true
+		}
+	}
+
+	var fields []string
+	if *fieldSpec != "" {
+		var err error
+		if fields, err = parseFields(*fieldSpec, SimplifiedEvent{}); err != nil {
+			exitWithError(err.Error())
+		}
+	}
+
+	switch *format {
+	case "json", "ics", "csv", "speech":
+	default:
+		exitWithError(fmt.Sprintf("Unknown --format %q (want json, ics, csv or speech)", *format))
+	}
+	if *chartPath != "" && !*thisWeek && !*nextWeek {
+		exitWithError("--chart needs --this-week or --next-week")
+	}
+	if *compare && !*thisWeek && !*nextWeek {
+		exitWithError("--compare needs --this-week or --next-week")
+	}
+	if *cost && !*thisWeek && !*nextWeek {
+		exitWithError("--cost needs --this-week or --next-week")
+	}
+	if *maxEvents < 1 {
+		exitWithError("--max must be at least 1")
+	}
+	if *slotMinutes < 1 {
+		exitWithError("--duration must be at least 1")
+	}
+	var rsvpResponse, rsvpEvent string
+	for response, id := range map[string]string{"accepted": *accept, "declined": *decline, "tentative": *tentative} {
+		if id == "" {
+			continue
+		}
+		if rsvpEvent != "" {
+			exitWithError("Use only one of --accept, --decline and --tentative")
+		}
+		rsvpResponse, rsvpEvent = response, id
+	}
+	switch *trackBy {
+	case "category", "color", "domain":
+	default:
+		exitWithError(fmt.Sprintf("Unknown --track-by %q (want category, color or domain)", *trackBy))
+	}
+	switch *groupBy {
+	case "", "day", "week", "account", "calendar":
+	default:
+		exitWithError(fmt.Sprintf("Unknown --group-by %q (want day, week, account or calendar)", *groupBy))
+	}
+	if *weeks < 0 {
+		exitWithError(fmt.Sprintf("--weeks %d must be positive", *weeks))
+	}
+	if *weeks > 0 && *groupBy == "" {
+		*groupBy = "week"
+	}
+	keywords, err := newKeywordFilter(*match, *exclude)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	var sizes map[string]bool
+	if *onlyOneOnOne {
+		*sizeSpec = "one_on_one"
+	}
+	if *sizeSpec != "" {
+		sizes = map[string]bool{}
+		for _, s := range strings.Split(*sizeSpec, ",") {
+			switch s = strings.TrimSpace(s); s {
+			case "one_on_one", "small", "large":
+				sizes[s] = true
+			default:
+				exitWithError(fmt.Sprintf("Unknown --size %q (want one_on_one, small or large)", s))
+			}
 		}
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		enc.SetEscapeHTML(false)
-		enc.Encode(errObj)
-		os.Exit(1)
 	}
 
-	gogDateArgs := buildGogArgs(*today, *tomorrow, *thisWeek, *nextWeek)
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if *tz == "" {
+		*tz = cfg.Timezone
+	}
+	if *locale == "" {
+		*locale = cfg.Locale
+	}
+	switch *locale {
+	case "":
+		*locale = "en"
+	case "en", "ko":
+	default:
+		exitWithError(fmt.Sprintf("Unknown --locale %q (want en or ko)", *locale))
+	}
+	loc := time.Local
+	if *tz != "" {
+		if loc, err = time.LoadLocation(*tz); err != nil {
+			exitWithError(fmt.Sprintf("Unknown timezone %q", *tz))
+		}
+	}
+
+	dayStart, dayEnd, err := resolveWorkingHours(*workHours, cfg.WorkingHours)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if *minGap <= 0 {
+		*minGap = cfg.MinGap
+	}
+	if *minGap <= 0 {
+		*minGap = 30
+	}
+	if *travelBuffer <= 0 {
+		*travelBuffer = cfg.TravelBufferMinutes
+	}
+	if *travelBuffer <= 0 {
+		*travelBuffer = 15
+	}
+
+	weekStart, err := resolveWeekStart(*weekStartFlag, cfg.WeekStart)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	now, err := currentTime()
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	now = now.In(loc)
+	rng, err := resolveDateRange(now, dateOptions{
+		Today: *today, Tomorrow: *tomorrow, ThisWeek: *thisWeek, NextWeek: *nextWeek,
+		Date: *date, From: *fromDate, To: *toDate, When: *when,
+		Month: *month, NextMonth: *nextMonth, Weekend: *weekend,
+		NextBusinessDay: *nextBusinessDay, Holidays: holidaySet(cfg.Holidays),
+		Next: *next, Upcoming: upcoming, WeekStart: weekStart, FreeAt: *freeAt, Weeks: *weeks,
+	}, *tz != "" || os.Getenv(nowEnv) != "")
+	if err != nil {
+		exitWithError(err.Error())
+	}
+
+	accounts, err := resolveAccounts(*personal, *work, cfg.ClassifyCommand)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	if len(accounts) == 0 {
+		exitWithError("No accounts found. Use --personal/--work or configure gog auth.")
+	}
+	if rsvpEvent != "" {
+		writeJSON(runRSVP(accounts, rsvpEvent, rsvpResponse, *requireApproval))
+		return
+	}
+	if *create {
+		ev, err := eventFromFlags(*title, *startAt, *endAt, *slotMinutes, *location, *invite, loc)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		target, err := pickAccount(accounts, *account)
+		if err != nil {
+			exitWithError(err.Error())
+		}
+		writeJSON(runCreate(target, ev, *requireApproval))
+		return
+	}
+	schedules, err := accountSchedules(accounts, cfg, dayStart, dayEnd)
+	if err != nil {
+		exitWithError(err.Error())
+	}
+	alertRules, err := compileAlertRules(cfg.AlertRules)
+	if err != nil {
+		exitWithError(err.Error())
+	}
 
 	var allEvents []SimplifiedEvent
 	var errors []AccountError
+	var prepCandidates []PrepBlock
+	var lintIssues []LintIssue
+	var rooms []roomBooking
+	var teammatesOOO []TeammateOOO
+	var personFiles []SharedFile
+	var declined []SimplifiedEvent
+	var seen []SimplifiedEvent // everything fetched, for --diff
+	var unread []string        // accounts not read this run, for --diff
 
-	for _, account := range accounts {
-		rawEvents, err := fetchEvents(account.Email, gogDateArgs)
+	meta := &Meta{}
+	truncated := false
+	health := loadHealth()
+	query := strings.Join(rng.GogArgs, " ")
+	// The holiday calendar for --free is read alongside the accounts.
+	var holidayEvents []SimplifiedEvent
+	var holidayErr error
+	var holidaysDone sync.WaitGroup
+	if *free {
+		holidaysDone.Add(1)
+		go func() {
+			defer holidaysDone.Done()
+			holidayEvents, holidayErr = fetchHolidays(cfg.HolidayCalendar, accounts[0].Email, rng.GogArgs)
+		}()
+	}
+	dropped := func(e GogEvent, simplified SimplifiedEvent) bool {
+		return (*hideCancelled && simplified.Status == "cancelled") || (*hideOptional && simplified.Optional) || (*hideFocusTime && simplified.EventType == "focusTime") || !keywords.keep(e) || (*person != "" && !involves(e, *person)) || (sizes != nil && !sizes[simplified.Size])
+	}
+	// So is the week before, for --compare.
+	prevFrom := rng.From.AddDate(0, 0, -7)
+	var prevEvents []SimplifiedEvent
+	var prevErrors []AccountError
+	var prevDone sync.WaitGroup
+	if *compare {
+		prevDone.Add(1)
+		go func() {
+			defer prevDone.Done()
+			prev := fetchAllEvents(accounts, windowGogArgs(prevFrom, rng.From), *maxEvents, jobOptions{Timeout: *accountTimeout, Retries: *retries}, health)
+			for i, account := range accounts {
+				if prev[i].err != nil {
+					prevErrors = append(prevErrors, AccountError{Email: account.Email, Error: fmt.Sprintf("previous week: %v", prev[i].err)})
+					continue
+				}
+				for _, e := range prev[i].events {
+					simplified := simplifyEvent(e, account.Type)
+					if dropped(e, simplified) {
+						continue
+					}
+					simplified.NoAgenda = lacksAgenda(e, simplified, *agendaMinutes)
+					simplified.account = account.Email
+					prevEvents = append(prevEvents, simplified)
+				}
+			}
+		}()
+	}
+	annotations := loadAnnotations()
+	results := fetchAllEvents(accounts, rng.GogArgs, *maxEvents, jobOptions{Timeout: *accountTimeout, Retries: *retries}, health)
+	holidaysDone.Wait()
+	prevDone.Wait()
+	for i, account := range accounts {
+		err := results[i].err
+		recordHealth(health, account.Email, err, now)
+		if err == nil {
+			saveFallback(account.Email, query, results[i].events, now)
+		} else if cached, ok := loadFallback(account.Email, query); ok {
+			results[i].events, results[i].meta.Status, results[i].meta.CachedAt = cached.Items, "cached", cached.FetchedAt
+			errors = append(errors, AccountError{Email: account.Email, Error: fmt.Sprintf("%v (showing events cached at %s)", err, cached.FetchedAt)})
+			err = nil
+		}
+		meta.Accounts = append(meta.Accounts, results[i].meta)
+		if results[i].err != nil {
+			unread = append(unread, account.Email)
+		}
 		if err != nil {
 			errors = append(errors, AccountError{Email: account.Email, Error: err.Error()})
 			continue
 		}
-		for _, e := range rawEvents {
-			allEvents = append(allEvents, simplifyEvent(e, account.Type))
+		truncated = truncated || results[i].truncated
+		for _, e := range results[i].events {
+			simplified := simplifyEvent(e, account.Type)
+			if *diff {
+				s := simplified
+				s.account = account.Email
+				if *redact {
+					redactEvent(&s)
+				}
+				if *tz != "" {
+					convertEventTimes(&s, loc)
+				}
+				seen = append(seen, s)
+			}
+			if dropped(e, simplified) {
+				continue
+			}
+			simplified.NoAgenda = lacksAgenda(e, simplified, *agendaMinutes)
+			annotate(&simplified, e, annotations)
+			if description > 0 {
+				simplified.Description = plainDescription(e.Description, int(description))
+			}
+			if *redact {
+				redactEvent(&simplified)
+			}
+			if simplified.Response == "declined" && simplified.Status != "cancelled" && !simplified.AllDay {
+				d := simplified
+				if *tz != "" {
+					convertEventTimes(&d, loc)
+				}
+				if !*withIDs {
+					d.EventID = ""
+				}
+				declined = append(declined, d)
+				if *hideDeclined {
+					continue
+				}
+			}
+			teammatesOOO = append(teammatesOOO, findTeammatesOOO(simplified)...)
+			if !*withAttendees {
+				simplified.Attendees = nil
+			}
+			// gog reads the primary calendar, whose ID is the account email.
+			simplified.account, simplified.calendar = account.Email, account.Email
+			if *tz != "" {
+				convertEventTimes(&simplified, loc)
+			}
+			allEvents = append(allEvents, simplified)
+			if *person != "" && !(*redact && simplified.private) {
+				personFiles = append(personFiles, meetingFiles(e, simplified)...)
+			}
+			if *prep {
+				if c, ok := newPrepCandidate(e, simplified, account.Email); ok {
+					prepCandidates = append(prepCandidates, c)
+				}
+			}
+			if *lint {
+				lintIssues = append(lintIssues, lintEvent(e, simplified, now)...)
+				rooms = append(rooms, collectRoomBookings(e, simplified)...)
+			}
+		}
+	}
+
+	if *onCall {
+		shifts, shiftErrors := fetchOnCallShifts(rng.From, rng.To)
+		errors = append(errors, shiftErrors...)
+		markHandoffCollisions(allEvents, shifts)
+		for _, s := range shifts {
+			allEvents = append(allEvents, shiftToEvent(s))
+		}
+	}
+
+	if *tasks {
+		perAccount := make([][]GogTask, len(accounts))
+		failed := make([]error, len(accounts))
+		parallel(len(accounts), func(i int) {
+			perAccount[i], failed[i] = fetchTasks(accounts[i].Email, rng.From, rng.To)
+		})
+		for i, account := range accounts {
+			if failed[i] != nil {
+				errors = append(errors, AccountError{Email: account.Email, Error: fmt.Sprintf("tasks: %v", failed[i])})
+				continue
+			}
+			for _, t := range perAccount[i] {
+				allEvents = append(allEvents, taskToEvent(t, account, int(description)))
+			}
 		}
 	}
 
@@ -326,17 +1067,231 @@ func main() {
 	if allEvents == nil {
 		allEvents = []SimplifiedEvent{}
 	}
+	allEvents = dedupeEvents(allEvents)
+	saveState("health.json", health)
+	// ICS keeps multi-day events whole; one VEVENT per UID.
+	if *format == "json" && rng.To.Sub(rng.From) > 24*time.Hour {
+		allEvents = expandAllDay(allEvents, rng.From, rng.To)
+	}
+	sortEvents(allEvents, loc)
+	if *next > 0 {
+		allEvents = upcomingEvents(allEvents, now, *next)
+	}
+	if *recordings {
+		perAccount := make([][]recordingMail, len(accounts))
+		failed := make([]error, len(accounts))
+		parallel(len(accounts), func(i int) {
+			perAccount[i], failed[i] = fetchRecordingMails(accounts[i].Email, rng.From, rng.To)
+		})
+		var mails []recordingMail
+		for i, account := range accounts {
+			if failed[i] != nil {
+				errors = append(errors, AccountError{Email: account.Email, Error: failed[i].Error()})
+				continue
+			}
+			mails = append(mails, perAccount[i]...)
+		}
+		attachRecordings(allEvents, mails, now)
+	}
+	if today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc); *next > 0 || upcoming || rng.From.Equal(today) && rng.To.Equal(today.AddDate(0, 0, 1)) {
+		setStartsIn(allEvents, now)
+	}
+
+	if *freeAt != "" {
+		// Already validated by resolveDateRange.
+		start, _ := parseDateTime(*freeAt, loc)
+		result := checkFreeAt(allEvents, start, time.Duration(*slotMinutes)*time.Minute, now, rng.To, dayStart, dayEnd)
+		result.Timezone, result.Errors, result.Meta = *tz, errors, meta
+		writeJSON(result)
+		return
+	}
+	if *doubleBooked {
+		writeJSON(DoubleBookedOutput{Timezone: *tz, DoubleBooked: findDoubleBooked(allEvents, loc), Errors: errors, Meta: meta})
+		return
+	}
+
+	conflicts := detectConflicts(allEvents)
+	longestStreak := markBackToBack(allEvents)
+	outsideMinutes := markOutsideHours(allEvents, schedules, loc)
+	markTravelWarnings(allEvents, newTravelEstimator(cfg.TravelCommand, *travelBuffer))
 
 	output := Output{
-		Accounts: accounts,
-		Events:   allEvents,
+		Timezone:  *tz,
+		Accounts:  accounts,
+		Events:    allEvents,
+		Conflicts: conflicts,
+		Truncated: truncated,
+		Meta:      meta,
+	}
+	output.TeammatesOOO = teammatesOOO
+	output.OnSite = findOnSiteDays(allEvents, loc)
+	if *person != "" {
+		output.Person = &PersonMeetings{Email: *person, Meetings: len(allEvents), Files: dedupeFiles(personFiles)}
+	}
+	if *alerts {
+		output.Alerts = checkAlerts(allEvents, alertRules, rng.From, rng.To)
+	}
+	if *diff {
+		output.Changes, output.DiffSince = diffEvents(seen, unread, rng.From, rng.To, now)
+	}
+	if !*withIDs {
+		for i := range allEvents {
+			allEvents[i].EventID, allEvents[i].HTMLLink = "", ""
+		}
+	}
+	if *prep {
+		output.Prep = findPrepNeeds(prepCandidates, allEvents)
+		if *apply {
+			for i, p := range output.Prep {
+				if p.HasPrepBlock {
+					continue
+				}
+				if *requireApproval {
+					staged, err := stageAction("prep_create", p.account, map[string]string{
+						"summary":      p.Summary,
+						"start":        p.Start,
+						"prep_minutes": strconv.Itoa(p.PrepMinutes),
+					})
+					if err != nil {
+						output.Prep[i].Error = err.Error()
+						continue
+					}
+					output.Prep[i].Staged = &staged
+					continue
+				}
+				if err := createPrepEvent(p); err != nil {
+					output.Prep[i].Error = err.Error()
+					continue
+				}
+				output.Prep[i].Created = true
+			}
+		}
+	}
+	if *lint {
+		output.Lint = append(lintIssues, lintRooms(rooms)...)
+	}
+	if *free {
+		if holidayErr != nil {
+			errors = append(errors, AccountError{Email: accounts[0].Email, Error: holidayErr.Error()})
+		}
+		off := findDaysOff(allEvents, holidayEvents, cfg.Holidays, loc)
+		output.FreeSlots = findFreeSlots(allEvents, rng.From, rng.To, now, dayStart, dayEnd, time.Duration(*minGap)*time.Minute, off)
+		output.DaysOff = daysOffIn(off, rng.From, rng.To)
+	}
+	if *thisWeek || *nextWeek {
+		stats := computeWeekStats(allEvents, rng.From, rng.To, dayStart, dayEnd)
+		stats.LongestStreakMinutes = longestStreak
+		stats.OutsideHoursMinutes = outsideMinutes
+		if *chartPath != "" {
+			if err := writeChart(*chartPath, meetingHoursChart(stats, loc)); err != nil {
+				exitWithError(fmt.Sprintf("Writing chart: %v", err))
+			}
+			stats.Chart = *chartPath
+		}
+		if *cost {
+			stats.Cost = computeMeetingCost(allEvents, rng.From, rng.To, cfg.HourlyRate)
+		}
+		if *compare {
+			previous := periodStats(prevEvents, prevFrom, rng.From, dayStart, dayEnd, schedules, loc)
+			stats.Compare = compareStats(stats, previous, prevFrom, rng.From)
+			errors = append(errors, prevErrors...)
+		}
+		output.Stats = &stats
+	}
+	if *timeline {
+		output.Timeline = buildTimeline(allEvents, rng.From, rng.To, dayStart, dayEnd)
+	}
+	output.Declined = findDeclined(declined, allEvents, loc, dayStart, dayEnd)
+	if *suggestDeclinesFlag {
+		history := fetchAllEvents(accounts, windowGogArgs(now.AddDate(0, 0, -declineHistoryDays), now), *maxEvents, jobOptions{Timeout: *accountTimeout, Retries: *retries}, health)
+		var past []GogEvent
+		for i, account := range accounts {
+			if history[i].err != nil {
+				errors = append(errors, AccountError{Email: account.Email, Error: fmt.Sprintf("decline history: %v", history[i].err)})
+				continue
+			}
+			past = append(past, history[i].events...)
+		}
+		output.SuggestedDeclines = suggestDeclines(past, now)
+	}
+	if rng.From.AddDate(0, 0, 1).Equal(rng.To) {
+		output.Day = computeDaySpan(allEvents, rng.From, rng.To)
 	}
 	if len(errors) > 0 {
 		output.Errors = errors
 	}
 
+	if *format != "json" {
+		switch *format {
+		case "ics":
+			writeICS(output.Events, now)
+		case "csv":
+			if err := writeTimesheet(output.Events, *trackBy, now, loc); err != nil {
+				exitWithError(err.Error())
+			}
+		case "speech":
+			if err := writeSpeech(os.Stdout, output, rng.From, rng.To, now, loc, *locale); err != nil {
+				exitWithError(err.Error())
+			}
+		}
+		if len(output.Errors) > 0 {
+			// Keep stdout a valid calendar, CSV or paragraph; report failed accounts on stderr.
+			enc := json.NewEncoder(os.Stderr)
+			enc.Encode(map[string][]AccountError{"errors": output.Errors})
+		}
+		return
+	}
+	if *splitAll {
+		output.Events, output.AllDayEvents = splitAllDay(output.Events)
+	}
+	if *templatePath != "" {
+		if err := renderTemplate(*templatePath, output, loc, *locale, now); err != nil {
+			exitWithError(fmt.Sprintf("Template failed: %v", err))
+		}
+		return
+	}
+
+	if fields == nil && *groupBy == "" {
+		writeJSON(output)
+		return
+	}
+	// Reshape the events: grouped sections replace the flat list, and
+	// --fields trims each event.
+	var events, allDay interface{} = output.Events, nil
+	var groups []EventGroup
+	if *groupBy != "" {
+		events, groups = nil, groupEvents(output.Events, *groupBy, loc)
+	}
+	if output.AllDayEvents != nil {
+		allDay = output.AllDayEvents
+	}
+	if fields != nil {
+		if events != nil {
+			events = projectFields(events, fields)
+		}
+		if allDay != nil {
+			allDay = projectFields(allDay, fields)
+		}
+		for i := range groups {
+			groups[i].Events = projectFields(groups[i].Events, fields)
+		}
+	}
+	writeJSON(struct {
+		Output
+		Events       interface{}  `json:"events,omitempty"`
+		AllDayEvents interface{}  `json:"all_day_events,omitempty"`
+		Groups       []EventGroup `json:"groups,omitempty"`
+	}{output, events, allDay, groups})
+}
+
+func writeJSON(v interface{}) {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	enc.SetEscapeHTML(false)
-	enc.Encode(output)
+	enc.Encode(v)
+}
+
+func exitWithError(msg string) {
+	writeJSON(map[string]string{"error": msg})
+	os.Exit(1)
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// --- Meeting Links ---
+
+var meetingProviders = []struct {
+	name    string
+	pattern *regexp.Regexp
+}{
+	{"zoom", regexp.MustCompile(`(?i)https?://[\w.-]*zoom\.us/[^\s"'<>]+`)},
+	{"google_meet", regexp.MustCompile(`(?i)https?://meet\.google\.com/[^\s"'<>]+`)},
+	{"teams", regexp.MustCompile(`(?i)https?://teams\.(?:microsoft|live)\.com/[^\s"'<>]+`)},
+	{"webex", regexp.MustCompile(`(?i)https?://[\w.-]*webex\.com/[^\s"'<>]+`)},
+}
+
+// findMeetingURL returns the first known conferencing URL in text.
+func findMeetingURL(text string) (string, string) {
+	text = html.UnescapeString(text)
+	for _, p := range meetingProviders {
+		if m := p.pattern.FindString(text); m != "" {
+			return strings.TrimRight(m, ".,;:)]"), p.name
+		}
+	}
+	return "", ""
+}
+
+// extractMeetingLink picks the join URL for an event, preferring structured
+// conference data over hangoutLink over URLs found in location and
+// description. Video entry points from unknown providers are reported as
+// "other".
+func extractMeetingLink(event GogEvent) (string, string) {
+	if event.ConferenceData != nil {
+		for _, ep := range event.ConferenceData.EntryPoints {
+			if ep.EntryPointType != "video" || ep.URI == "" {
+				continue
+			}
+			if _, provider := findMeetingURL(ep.URI); provider != "" {
+				return ep.URI, provider
+			}
+			return ep.URI, "other"
+		}
+	}
+	if event.HangoutLink != "" {
+		return event.HangoutLink, "google_meet"
+	}
+	if url, provider := findMeetingURL(event.Location); url != "" {
+		return url, provider
+	}
+	return findMeetingURL(event.Description)
+}
@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+// --- Meeting Links ---
+
+func TestFindMeetingURL(t *testing.T) {
+	cases := []struct {
+		text, url, provider string
+	}{
+		{"Join: https://corp.zoom.us/j/123456789?pwd=abc.", "https://corp.zoom.us/j/123456789?pwd=abc", "zoom"},
+		{"https://zoom.us/my/alice", "https://zoom.us/my/alice", "zoom"},
+		{"Meet at https://meet.google.com/abc-defg-hij)", "https://meet.google.com/abc-defg-hij", "google_meet"},
+		{`<a href="https://teams.microsoft.com/l/meetup-join/19%3ameeting_x%40thread.v2/0?context=%7b%7d">Join</a>`,
+			"https://teams.microsoft.com/l/meetup-join/19%3ameeting_x%40thread.v2/0?context=%7b%7d", "teams"},
+		{"https://teams.live.com/meet/9876", "https://teams.live.com/meet/9876", "teams"},
+		{"Webex: https://corp.webex.com/corp/j.php?MTID=m1&amp;x=2", "https://corp.webex.com/corp/j.php?MTID=m1&x=2", "webex"},
+		{"Room 4B, no link", "", ""},
+		{"https://example.com/zoom.us/fake", "", ""},
+	}
+	for _, c := range cases {
+		url, provider := findMeetingURL(c.text)
+		if url != c.url || provider != c.provider {
+			t.Errorf("findMeetingURL(%q) = %q, %q; want %q, %q", c.text, url, provider, c.url, c.provider)
+		}
+	}
+}
+
+func TestExtractMeetingLink(t *testing.T) {
+	video := func(uri string) *GogConference {
+		return &GogConference{EntryPoints: []GogEntryPoint{{EntryPointType: "phone", URI: "tel:+1-555-0100"}, {EntryPointType: "video", URI: uri}}}
+	}
+	cases := []struct {
+		name          string
+		event         GogEvent
+		url, provider string
+	}{
+		{"conference data first", GogEvent{
+			ConferenceData: video("https://meet.google.com/abc-defg-hij"),
+			HangoutLink:    "https://meet.google.com/zzz-zzzz-zzz",
+			Location:       "https://corp.zoom.us/j/1",
+		}, "https://meet.google.com/abc-defg-hij", "google_meet"},
+		{"conference data from an add-on", GogEvent{ConferenceData: video("https://corp.zoom.us/j/42")}, "https://corp.zoom.us/j/42", "zoom"},
+		{"unknown provider", GogEvent{ConferenceData: video("https://meet.jit.si/standup")}, "https://meet.jit.si/standup", "other"},
+		{"hangoutLink over location", GogEvent{
+			HangoutLink: "https://meet.google.com/abc-defg-hij",
+			Location:    "https://corp.zoom.us/j/1",
+		}, "https://meet.google.com/abc-defg-hij", "google_meet"},
+		{"location over description", GogEvent{
+			Location:    "https://corp.webex.com/meet/alice",
+			Description: "Backup: https://teams.microsoft.com/l/meetup-join/1",
+		}, "https://corp.webex.com/meet/alice", "webex"},
+		{"description", GogEvent{
+			Location:    "Room 4B",
+			Description: "Dial in<br>https://teams.microsoft.com/l/meetup-join/1<br>",
+		}, "https://teams.microsoft.com/l/meetup-join/1", "teams"},
+		{"phone only", GogEvent{ConferenceData: &GogConference{EntryPoints: []GogEntryPoint{{EntryPointType: "phone", URI: "tel:+1"}}}}, "", ""},
+		{"nothing", GogEvent{Location: "Cafeteria"}, "", ""},
+	}
+	for _, c := range cases {
+		url, provider := extractMeetingLink(c.event)
+		if url != c.url || provider != c.provider {
+			t.Errorf("%s: extractMeetingLink = %q, %q; want %q, %q", c.name, url, provider, c.url, c.provider)
+		}
+	}
+}
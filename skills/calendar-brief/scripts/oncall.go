@@ -0,0 +1,228 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// --- On-call Shifts ---
+
+type onCallShift struct {
+	Source   string
+	Schedule string
+	Start    time.Time
+	End      time.Time
+
+	// OpenStart and OpenEnd mark bounds clamped to the requested window
+	// because the shift has none there; nobody hands off at those instants.
+	OpenStart, OpenEnd bool
+}
+
+// fetchOnCallShifts pulls shifts from every configured on-call source. Sources
+// are configured through environment variables so API keys never end up in
+// shell history or skill arguments:
+//
+//	PAGERDUTY_TOKEN, PAGERDUTY_USER_ID
+//	OPSGENIE_API_KEY, OPSGENIE_SCHEDULE, OPSGENIE_USER
+func fetchOnCallShifts(from, to time.Time) ([]onCallShift, []AccountError) {
+	var shifts []onCallShift
+	var errors []AccountError
+
+	if token := os.Getenv("PAGERDUTY_TOKEN"); token != "" {
+		s, err := fetchPagerDutyShifts(token, os.Getenv("PAGERDUTY_USER_ID"), from, to)
+		if err != nil {
+			errors = append(errors, AccountError{Email: "pagerduty", Error: err.Error()})
+		}
+		shifts = append(shifts, s...)
+	}
+	if key := os.Getenv("OPSGENIE_API_KEY"); key != "" {
+		s, err := fetchOpsgenieShifts(key, os.Getenv("OPSGENIE_SCHEDULE"), os.Getenv("OPSGENIE_USER"), from, to)
+		if err != nil {
+			errors = append(errors, AccountError{Email: "opsgenie", Error: err.Error()})
+		}
+		shifts = append(shifts, s...)
+	}
+	if len(shifts) == 0 && len(errors) == 0 {
+		errors = append(errors, AccountError{
+			Email: "on-call",
+			Error: "No on-call source configured. Set PAGERDUTY_TOKEN or OPSGENIE_API_KEY.",
+		})
+	}
+	return shifts, errors
+}
+
+func getJSON(rawURL, authHeader string, headers map[string]string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authHeader)
+	for k, val := range headers {
+		req.Header.Set(k, val)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func fetchPagerDutyShifts(token, userID string, from, to time.Time) ([]onCallShift, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("PAGERDUTY_USER_ID is required")
+	}
+
+	q := url.Values{}
+	q.Set("user_ids[]", userID)
+	q.Set("since", from.Format(time.RFC3339))
+	q.Set("until", to.Format(time.RFC3339))
+
+	var data struct {
+		OnCalls []struct {
+			Schedule *struct {
+				Summary string `json:"summary"`
+			} `json:"schedule"`
+			EscalationPolicy struct {
+				Summary string `json:"summary"`
+			} `json:"escalation_policy"`
+			Start *string `json:"start"`
+			End   *string `json:"end"`
+		} `json:"oncalls"`
+	}
+	err := getJSON("https://api.pagerduty.com/oncalls?"+q.Encode(), "Token token="+token,
+		map[string]string{"Accept": "application/vnd.pagerduty+json;version=2"}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := make([]onCallShift, 0, len(data.OnCalls))
+	for _, oc := range data.OnCalls {
+		name := oc.EscalationPolicy.Summary
+		if oc.Schedule != nil && oc.Schedule.Summary != "" {
+			name = oc.Schedule.Summary
+		}
+		shift := onCallShift{Source: "pagerduty", Schedule: name}
+		shift.Start, shift.OpenStart = pagerDutyBound(oc.Start, from)
+		shift.End, shift.OpenEnd = pagerDutyBound(oc.End, to)
+		shifts = append(shifts, shift)
+	}
+	return shifts, nil
+}
+
+// pagerDutyBound parses a shift's start or end. A nil one means "always on
+// call" for the escalation policy; it is clamped to edge, the window's
+// bound, and reported as open.
+func pagerDutyBound(value *string, edge time.Time) (time.Time, bool) {
+	if value != nil {
+		if t, err := time.Parse(time.RFC3339, *value); err == nil {
+			return t, false
+		}
+	}
+	return edge, true
+}
+
+func fetchOpsgenieShifts(key, schedule, user string, from, to time.Time) ([]onCallShift, error) {
+	if schedule == "" {
+		return nil, fmt.Errorf("OPSGENIE_SCHEDULE is required")
+	}
+	// The timeline lists every recipient of the schedule; without a user
+	// the whole team's shifts would read as mine.
+	if user == "" {
+		return nil, fmt.Errorf("OPSGENIE_USER is required")
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	q := url.Values{}
+	q.Set("identifierType", "name")
+	q.Set("interval", fmt.Sprintf("%d", days))
+	q.Set("intervalUnit", "days")
+	q.Set("date", from.Format(time.RFC3339))
+
+	var data struct {
+		Data struct {
+			FinalTimeline struct {
+				Rotations []struct {
+					Periods []struct {
+						StartDate string `json:"startDate"`
+						EndDate   string `json:"endDate"`
+						Recipient struct {
+							Name string `json:"name"`
+						} `json:"recipient"`
+					} `json:"periods"`
+				} `json:"rotations"`
+			} `json:"finalTimeline"`
+		} `json:"data"`
+	}
+	rawURL := fmt.Sprintf("https://api.opsgenie.com/v2/schedules/%s/timeline?%s", url.PathEscape(schedule), q.Encode())
+	if err := getJSON(rawURL, "GenieKey "+key, nil, &data); err != nil {
+		return nil, err
+	}
+
+	var shifts []onCallShift
+	for _, r := range data.Data.FinalTimeline.Rotations {
+		for _, p := range r.Periods {
+			if !strings.EqualFold(p.Recipient.Name, user) {
+				continue
+			}
+			start, err1 := time.Parse(time.RFC3339, p.StartDate)
+			end, err2 := time.Parse(time.RFC3339, p.EndDate)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			shifts = append(shifts, onCallShift{Source: "opsgenie", Schedule: schedule, Start: start, End: end})
+		}
+	}
+	return shifts, nil
+}
+
+func shiftToEvent(s onCallShift) SimplifiedEvent {
+	return SimplifiedEvent{
+		Summary:     fmt.Sprintf("On-call: %s", s.Schedule),
+		Start:       s.Start.Format(time.RFC3339),
+		End:         s.End.Format(time.RFC3339),
+		Status:      "confirmed",
+		AccountType: "work",
+		OnCall:      true,
+		account:     s.Source,
+		calendar:    s.Source + ":" + s.Schedule,
+	}
+}
+
+// markHandoffCollisions flags events that are in progress at the moment an
+// on-call shift starts or ends. Open bounds are only the edges of the
+// window, not handoffs, so they never collide.
+func markHandoffCollisions(events []SimplifiedEvent, shifts []onCallShift) {
+	for i := range events {
+		if events[i].OnCall {
+			continue
+		}
+		start, err1 := time.Parse(time.RFC3339, events[i].Start)
+		end, err2 := time.Parse(time.RFC3339, events[i].End)
+		if err1 != nil || err2 != nil {
+			continue // all-day events cannot collide with a handoff instant
+		}
+		for _, s := range shifts {
+			startsDuring := !s.OpenStart && !s.Start.Before(start) && s.Start.Before(end)
+			endsDuring := !s.OpenEnd && !s.End.Before(start) && s.End.Before(end)
+			if startsDuring || endsDuring {
+				events[i].OnCallHandoff = true
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPagerDutyBound(t *testing.T) {
+	edge := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	start := "2026-10-17T09:00:00Z"
+	bad := "soon"
+	if got, open := pagerDutyBound(&start, edge); open || got.Hour() != 9 {
+		t.Errorf("set bound: got %v open=%v", got, open)
+	}
+	for _, v := range []*string{nil, &bad} {
+		if got, open := pagerDutyBound(v, edge); !open || !got.Equal(edge) {
+			t.Errorf("missing bound: got %v open=%v, want the edge, open", got, open)
+		}
+	}
+}
+
+func TestMarkHandoffCollisions(t *testing.T) {
+	from := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	at := func(clock string) string { return "2026-10-17T" + clock + ":00Z" }
+	events := []SimplifiedEvent{
+		{Summary: "Midnight deploy", Start: "2026-10-16T23:30:00Z", End: at("00:30")},
+		{Summary: "Standup", Start: at("09:00"), End: at("09:30")},
+		{Summary: "Review", Start: at("17:30"), End: at("18:30")},
+		{Summary: "Late call", Start: at("23:30"), End: "2026-10-18T00:30:00Z"},
+		{Summary: "Offsite", Start: "2026-10-17", End: "2026-10-18"},
+	}
+	shifts := []onCallShift{
+		// Always on call: clamped to the window on both sides.
+		{Source: "pagerduty", Schedule: "Escalation", Start: from, End: to, OpenStart: true, OpenEnd: true},
+		// A real shift handing off at 18:00.
+		{Source: "pagerduty", Schedule: "Primary", Start: time.Date(2026, 10, 16, 18, 0, 0, 0, time.UTC), End: time.Date(2026, 10, 17, 18, 0, 0, 0, time.UTC)},
+	}
+	markHandoffCollisions(events, shifts)
+	want := map[string]bool{"Review": true}
+	for _, e := range events {
+		if e.OnCallHandoff != want[e.Summary] {
+			t.Errorf("%s: handoff %v, want %v", e.Summary, e.OnCallHandoff, want[e.Summary])
+		}
+	}
+
+	// Without the open marks, the window's edges would look like handoffs.
+	shifts[0].OpenStart, shifts[0].OpenEnd = false, false
+	markHandoffCollisions(events, shifts)
+	if !events[0].OnCallHandoff {
+		t.Error("a closed shift starting at midnight should collide with the midnight deploy")
+	}
+}
+
+// Missing user settings are reported before any request is made.
+func TestOnCallSourcesRequireUser(t *testing.T) {
+	from := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	if _, err := fetchOpsgenieShifts("key", "Ops", "", from, to); err == nil || err.Error() != "OPSGENIE_USER is required" {
+		t.Errorf("opsgenie without a user: %v", err)
+	}
+	if _, err := fetchPagerDutyShifts("token", "", from, to); err == nil || err.Error() != "PAGERDUTY_USER_ID is required" {
+		t.Errorf("pagerduty without a user: %v", err)
+	}
+
+	t.Setenv("PAGERDUTY_TOKEN", "")
+	t.Setenv("OPSGENIE_API_KEY", "key")
+	t.Setenv("OPSGENIE_SCHEDULE", "Ops")
+	t.Setenv("OPSGENIE_USER", "")
+	shifts, errs := fetchOnCallShifts(from, to)
+	if len(shifts) != 0 || len(errs) != 1 || errs[0].Email != "opsgenie" {
+		t.Errorf("got shifts %v, errors %v; want one opsgenie error", shifts, errs)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// --- Approval Gate ---
+
+// With --require-approval, write actions are not performed but staged in
+// pending.json in the state directory. --pending lists them and --approve
+// runs them, so a person signs off before the calendar changes.
+
+type PendingAction struct {
+	ID      string            `json:"id"`
+	Staged  string            `json:"staged"`
+	Action  string            `json:"action"` // prep_create, create or rsvp
+	Account string            `json:"account"`
+	Params  map[string]string `json:"params"`
+}
+
+type ApprovalResult struct {
+	PendingAction
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+}
+
+type PendingOutput struct {
+	Pending  []PendingAction  `json:"pending"`
+	Approved []ApprovalResult `json:"approved,omitempty"`
+	Errors   []AccountError   `json:"errors,omitempty"`
+}
+
+func loadPending() []PendingAction {
+	pending := []PendingAction{}
+	loadState("pending.json", &pending)
+	return pending
+}
+
+// stageAction queues an action for approval and returns it.
+func stageAction(action, account string, params map[string]string) (PendingAction, error) {
+	now := time.Now()
+	p := PendingAction{
+		ID:      strconv.FormatInt(now.UnixNano(), 36),
+		Staged:  now.Format(time.RFC3339),
+		Action:  action,
+		Account: account,
+		Params:  params,
+	}
+	return p, saveState("pending.json", append(loadPending(), p))
+}
+
+// executePending performs a staged action the way the original command would
+// have without --require-approval.
+func executePending(p PendingAction) error {
+	switch p.Action {
+	case "prep_create":
+		minutes, err := strconv.Atoi(p.Params["prep_minutes"])
+		if err != nil {
+			return fmt.Errorf("invalid prep_minutes %q", p.Params["prep_minutes"])
+		}
+		return createPrepEvent(PrepBlock{Summary: p.Params["summary"], Start: p.Params["start"], PrepMinutes: minutes, account: p.Account})
+	case "create":
+		ev, err := stagedEvent(p.Params)
+		if err != nil {
+			return err
+		}
+		_, err = createEvent(p.Account, ev)
+		return err
+	case "rsvp":
+		return respondToEvent(p.Account, p.Params["event_id"], p.Params["response"], p.Params["previous"])
+	}
+	return fmt.Errorf("unknown pending action %q", p.Action)
+}
+
+// runApprove executes the pending action with the given ID, or all of them
+// for ALL. Actions that fail stay pending so they can be retried.
+func runApprove(target string) PendingOutput {
+	output := PendingOutput{Pending: []PendingAction{}}
+	pending := loadPending()
+	found := false
+	for _, p := range pending {
+		if target != "ALL" && p.ID != target {
+			output.Pending = append(output.Pending, p)
+			continue
+		}
+		found = true
+		result := ApprovalResult{PendingAction: p}
+		if err := executePending(p); err != nil {
+			result.Error = err.Error()
+			output.Pending = append(output.Pending, p)
+		} else {
+			result.Done = true
+		}
+		output.Approved = append(output.Approved, result)
+	}
+	if !found && target != "ALL" {
+		output.Errors = append(output.Errors, AccountError{Error: fmt.Sprintf("no pending action %s", target)})
+		return output
+	}
+	if err := saveState("pending.json", output.Pending); err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+	}
+	return output
+}
@@ -0,0 +1,62 @@
+package main
+
+import "strings"
+
+// --- Person View ---
+
+// --person narrows the brief to meetings with one person and collects the
+// files attached to them. Without a date flag it looks at the upcoming
+// meetings, the same window --next uses.
+
+type PersonMeetings struct {
+	Email    string       `json:"email"`
+	Meetings int          `json:"meetings"` // events in this brief with them
+	Files    []SharedFile `json:"files"`
+}
+
+type SharedFile struct {
+	Title   string `json:"title"`
+	URL     string `json:"url,omitempty"`
+	Meeting string `json:"meeting"` // summary of the event it is attached to
+	Start   string `json:"start"`
+}
+
+// involves reports whether email organizes or is invited to event. A
+// declined invitation still counts; the meeting is about them either way.
+func involves(event GogEvent, email string) bool {
+	if strings.EqualFold(event.Organizer.Email, email) {
+		return true
+	}
+	for _, a := range event.Attendees {
+		if !a.Resource && strings.EqualFold(a.Email, email) {
+			return true
+		}
+	}
+	return false
+}
+
+func meetingFiles(event GogEvent, e SimplifiedEvent) []SharedFile {
+	var files []SharedFile
+	for _, a := range event.Attachments {
+		title := a.Title
+		if title == "" {
+			title = a.FileURL
+		}
+		files = append(files, SharedFile{Title: title, URL: a.FileURL, Meeting: e.Summary, Start: e.Start})
+	}
+	return files
+}
+
+// dedupeFiles drops repeats of a file attached to the same meeting, as seen
+// from more than one account.
+func dedupeFiles(files []SharedFile) []SharedFile {
+	seen := map[SharedFile]bool{}
+	out := []SharedFile{}
+	for _, f := range files {
+		if !seen[f] {
+			seen[f] = true
+			out = append(out, f)
+		}
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// --- Worker Pool ---
+
+// Every gog fetch the run starts, whether for an account, an extra calendar
+// or the next page of a listing, first takes a slot from gogPool, so
+// --concurrency caps the run as a whole rather than one loop in it.
+// Independent work fans out with parallel and leaves the throttling to the
+// pool. Pages of one listing still follow each other, since each needs the
+// previous page's token.
+
+const defaultConcurrency = 4
+
+var gogPool = newWorkerPool(defaultConcurrency)
+
+type workerPool struct {
+	slots chan struct{}
+}
+
+func newWorkerPool(n int) *workerPool {
+	if n < 1 {
+		n = 1
+	}
+	return &workerPool{slots: make(chan struct{}, n)}
+}
+
+// acquire waits for a free slot or for ctx to end. release must be called
+// once the process has exited.
+func (p *workerPool) acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case p.slots <- struct{}{}:
+		return func() { <-p.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// parallel runs fn(0) through fn(n-1) concurrently and waits for them all.
+func parallel(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // registers /debug/pprof/ on the default mux
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+)
+
+// --- Profiling ---
+
+// startProfiling handles --pprof. A host:port (or :port) serves
+// net/http/pprof for the life of the run; anything else is a directory that
+// receives cpu.pprof for the whole run and heap.pprof at the end. The
+// returned stop must run before exit.
+func startProfiling(spec string) (func(), error) {
+	if strings.Contains(spec, ":") {
+		ln, err := net.Listen("tcp", spec)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "calendar-brief: pprof on http://%s/debug/pprof/\n", ln.Addr())
+		go http.Serve(ln, nil)
+		return func() { ln.Close() }, nil
+	}
+
+	if err := os.MkdirAll(spec, 0o755); err != nil {
+		return nil, err
+	}
+	cpu, err := os.Create(filepath.Join(spec, "cpu.pprof"))
+	if err != nil {
+		return nil, err
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, err
+	}
+	return func() {
+		pprof.StopCPUProfile()
+		cpu.Close()
+		heap, err := os.Create(filepath.Join(spec, "heap.pprof"))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "calendar-brief: heap profile not written: %v\n", err)
+			return
+		}
+		defer heap.Close()
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(heap); err != nil {
+			fmt.Fprintf(os.Stderr, "calendar-brief: heap profile not written: %v\n", err)
+		}
+	}, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// --- Prep-time Blocking ---
+
+type PrepBlock struct {
+	Summary      string         `json:"summary"`
+	Start        string         `json:"start"`
+	Tags         []string       `json:"tags"`
+	PrepMinutes  int            `json:"prep_minutes"`
+	HasPrepBlock bool           `json:"has_prep_block"`
+	Created      bool           `json:"created,omitempty"`
+	Staged       *PendingAction `json:"staged,omitempty"` // with --require-approval
+	Error        string         `json:"error,omitempty"`
+
+	account string
+	uid     string // iCalUID, to merge copies seen by several accounts
+}
+
+// prepMinutesByTag is how much preparation each kind of meeting needs. When an
+// event carries several tags, the largest requirement wins.
+var prepMinutesByTag = map[string]int{
+	"interview":       30,
+	"external":        15,
+	"organized_by_me": 15,
+}
+
+func prepTags(event GogEvent, accountEmail string) []string {
+	var tags []string
+
+	summary := strings.ToLower(event.Summary)
+	if strings.Contains(summary, "interview") || strings.Contains(summary, "면접") {
+		tags = append(tags, "interview")
+	}
+
+	myDomain := emailDomain(accountEmail)
+	for _, a := range event.Attendees {
+		if a.Resource {
+			continue
+		}
+		if d := emailDomain(a.Email); d != "" && d != myDomain {
+			tags = append(tags, "external")
+			break
+		}
+	}
+
+	if event.Organizer.Self {
+		tags = append(tags, "organized_by_me")
+	}
+	return tags
+}
+
+func emailDomain(email string) string {
+	parts := strings.SplitN(email, "@", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.ToLower(parts[1])
+}
+
+func isPrepBlock(e SimplifiedEvent) bool {
+	s := strings.ToLower(e.Summary)
+	return strings.HasPrefix(s, "prep") || strings.Contains(s, "준비")
+}
+
+// dedupePrepCandidates merges candidates for the same meeting seen by
+// several accounts, keyed like dedupeEvents, so --apply creates one prep
+// event per meeting. The first account's copy is kept, with every copy's
+// tags and the largest prep time.
+func dedupePrepCandidates(candidates []PrepBlock) []PrepBlock {
+	merged := make([]PrepBlock, 0, len(candidates))
+	index := map[string]int{}
+	for _, c := range candidates {
+		key := copyKey(c.uid, c.Summary, c.Start)
+		i, seen := index[key]
+		if !seen {
+			index[key] = len(merged)
+			merged = append(merged, c)
+			continue
+		}
+		for _, t := range c.Tags {
+			if !containsString(merged[i].Tags, t) {
+				merged[i].Tags = append(merged[i].Tags, t)
+			}
+		}
+		if c.PrepMinutes > merged[i].PrepMinutes {
+			merged[i].PrepMinutes = c.PrepMinutes
+		}
+	}
+	return merged
+}
+
+// findPrepNeeds merges the candidates of shared meetings and records
+// whether a prep event already ends within each one's prep window.
+func findPrepNeeds(candidates []PrepBlock, events []SimplifiedEvent) []PrepBlock {
+	candidates = dedupePrepCandidates(candidates)
+	for i := range candidates {
+		start, err := time.Parse(time.RFC3339, candidates[i].Start)
+		if err != nil {
+			continue
+		}
+		windowStart := start.Add(-time.Duration(candidates[i].PrepMinutes) * time.Minute)
+		for _, e := range events {
+			if !isPrepBlock(e) {
+				continue
+			}
+			end, err := time.Parse(time.RFC3339, e.End)
+			if err != nil {
+				continue
+			}
+			if end.After(windowStart) && !end.After(start) {
+				candidates[i].HasPrepBlock = true
+				break
+			}
+		}
+	}
+	return candidates
+}
+
+func newPrepCandidate(event GogEvent, simplified SimplifiedEvent, accountEmail string) (PrepBlock, bool) {
+	if _, err := time.Parse(time.RFC3339, simplified.Start); err != nil {
+		return PrepBlock{}, false // all-day events do not need a prep block
+	}
+	if simplified.Response == "declined" || simplified.Status == "cancelled" || isPrepBlock(simplified) {
+		return PrepBlock{}, false
+	}
+
+	tags := prepTags(event, accountEmail)
+	if len(tags) == 0 {
+		return PrepBlock{}, false
+	}
+	minutes := 0
+	for _, t := range tags {
+		if prepMinutesByTag[t] > minutes {
+			minutes = prepMinutesByTag[t]
+		}
+	}
+	return PrepBlock{
+		Summary:     simplified.Summary,
+		Start:       simplified.Start,
+		Tags:        tags,
+		PrepMinutes: minutes,
+		account:     accountEmail,
+		uid:         simplified.uid,
+	}, true
+}
+
+func createPrepEvent(p PrepBlock) error {
+	start, err := time.Parse(time.RFC3339, p.Start)
+	if err != nil {
+		return err
+	}
+	_, err = createEvent(p.account, newEvent{
+		Summary: fmt.Sprintf("Prep: %s", p.Summary),
+		Start:   start.Add(-time.Duration(p.PrepMinutes) * time.Minute),
+		End:     start,
+	})
+	return err
+}
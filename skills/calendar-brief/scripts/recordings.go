@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// --- Recording & Notes Links ---
+
+type RecordingLink struct {
+	Kind     string `json:"kind"`     // recording, transcript or notes
+	Provider string `json:"provider"` // meet, zoom or gong
+	Subject  string `json:"subject"`
+	URL      string `json:"url"`
+}
+
+type recordingMail struct {
+	RecordingLink
+	received time.Time
+}
+
+// recordingQuery finds the notification mail that Meet, Zoom and Gong send
+// once a recording, transcript or AI notes are ready.
+const recordingQuery = `(from:meet-recordings-noreply@google.com OR from:gemini-notes@google.com OR from:no-reply@zoom.us OR from:gong.io OR subject:recording OR subject:transcript OR subject:녹화)`
+
+var (
+	recordingKindPattern = regexp.MustCompile(`(?i)(transcript|녹취)|(notes|recap|summary|회의록)|(recording|녹화)`)
+	recordingURLPattern  = regexp.MustCompile(`https?://[^\s"'<>]+`)
+	subjectNoisePattern  = regexp.MustCompile(`(?i)^(re|fwd?):\s*|meeting recording|cloud recording|recording|transcript|notes( by gemini)?|your call recap|is now available|녹화|[-–:"“”]`)
+)
+
+func recordingProvider(from string) string {
+	switch from = strings.ToLower(from); {
+	case strings.Contains(from, "zoom"):
+		return "zoom"
+	case strings.Contains(from, "gong"):
+		return "gong"
+	case strings.Contains(from, "google.com"):
+		return "meet"
+	}
+	return ""
+}
+
+func fetchRecordingMails(accountEmail string, from, to time.Time) ([]recordingMail, error) {
+	query := fmt.Sprintf("%s after:%s before:%s", recordingQuery, from.Format("2006/01/02"), to.AddDate(0, 0, 1).Format("2006/01/02"))
+	args := []string{"gmail", "messages", "search", query, "--json", "--max=50", fmt.Sprintf("--account=%s", accountEmail)}
+
+	var list gogList[GogMessage]
+	err := streamGog(context.Background(), func(r io.Reader) error {
+		var err error
+		list, err = decodeList[GogMessage](r, "messages")
+		return err
+	}, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var mails []recordingMail
+	for _, m := range list.Items {
+		sender := m.From
+		provider := recordingProvider(sender)
+		subject := m.Subject
+		if provider == "" {
+			continue
+		}
+		received, err := time.ParseInLocation("2006-01-02 15:04", m.Date, time.Local)
+		if err != nil {
+			continue
+		}
+		kind := "recording"
+		if k := recordingKindPattern.FindStringSubmatch(subject); k != nil {
+			switch {
+			case k[1] != "":
+				kind = "transcript"
+			case k[2] != "":
+				kind = "notes"
+			}
+		}
+		// Search results carry no body, so fall back to the mail itself.
+		url := recordingURLPattern.FindString(m.Snippet)
+		if url == "" {
+			url = fmt.Sprintf("https://mail.google.com/mail/u/%s/#all/%s", accountEmail, m.ThreadID)
+		}
+		mails = append(mails, recordingMail{
+			RecordingLink: RecordingLink{Kind: kind, Provider: provider, Subject: subject, URL: url},
+			received:      received.In(from.Location()),
+		})
+	}
+	return mails, nil
+}
+
+func titleWords(s string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.Fields(strings.ToLower(subjectNoisePattern.ReplaceAllString(s, " "))) {
+		words[w] = true
+	}
+	return words
+}
+
+// titlesMatch reports whether a notification subject names the meeting: at
+// least half the meeting title's words appear in the subject.
+func titlesMatch(summary, subject string) bool {
+	want, have := titleWords(summary), titleWords(subject)
+	if len(want) == 0 {
+		return false
+	}
+	hits := 0
+	for w := range want {
+		if have[w] {
+			hits++
+		}
+	}
+	return hits*2 >= len(want)
+}
+
+// attachRecordings links each ended event to recording mail that arrived
+// within a day of its start and whose subject matches its title.
+func attachRecordings(events []SimplifiedEvent, mails []recordingMail, now time.Time) {
+	for i := range events {
+		start, end, ok := blocksTime(events[i])
+		if !ok || end.After(now) {
+			continue
+		}
+		for _, m := range mails {
+			if m.received.Before(start) || m.received.After(start.Add(24*time.Hour)) {
+				continue
+			}
+			if titlesMatch(events[i].Summary, m.Subject) {
+				events[i].Recordings = append(events[i].Recordings, m.RecordingLink)
+			}
+		}
+	}
+}
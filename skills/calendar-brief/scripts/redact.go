@@ -0,0 +1,38 @@
+package main
+
+// --- Redaction ---
+
+// --redact makes a brief safe to paste into a shared channel. Events marked
+// private (or confidential) keep only their time and show as "Busy"; every
+// event loses attendee and organizer email addresses.
+
+const redactedSummary = "Busy"
+
+// isPrivate reports whether the organizer hid the event's details from
+// people who can see the calendar.
+func isPrivate(event GogEvent) bool {
+	return event.Visibility == "private" || event.Visibility == "confidential"
+}
+
+// redactEvent strips e in place. It runs before anything is derived from
+// the event, so conflicts, alerts and the rest only ever see the redacted
+// copy.
+func redactEvent(e *SimplifiedEvent) {
+	if e.private {
+		e.Summary = redactedSummary
+		e.Location, e.Room, e.Description = "", nil, ""
+		e.MeetingURL, e.MeetingProvider, e.HTMLLink = "", "", ""
+		e.OrganizerName = ""
+		e.Attendees, e.Recordings = nil, nil
+		e.Tags, e.Note = nil, ""
+	}
+	e.OrganizerEmail = ""
+	for i := range e.Attendees {
+		e.Attendees[i].Email = ""
+	}
+	if e.Room != nil {
+		room := *e.Room
+		room.Email = ""
+		e.Room = &room
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+// --- Reminders ---
+
+type Reminder struct {
+	Method        string `json:"method"` // popup or email
+	MinutesBefore int    `json:"minutes_before"`
+}
+
+// extractReminders reads an event's reminders: its own overrides, or
+// whether it falls back to the calendar's defaults. none is set only when
+// gog reported reminders and there are none at all; an event printed
+// without the reminders object is not flagged, since there is no telling.
+func extractReminders(event GogEvent) (reminders []Reminder, useDefault, none bool) {
+	r := event.Reminders
+	if r == nil {
+		return nil, false, false
+	}
+	for _, o := range r.Overrides {
+		reminders = append(reminders, Reminder{Method: o.Method, MinutesBefore: o.Minutes})
+	}
+	return reminders, r.UseDefault, !r.UseDefault && len(reminders) == 0
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Rooms ---
+
+// Rooms are booked as resource attendees. Google Workspace names them
+// "Building-Floor-Room (capacity) [features]", e.g. "Seoul HQ-3-Aurora (8)
+// [TV]"; hand-named rooms often spell the floor out instead, as in
+// "Gangnam 12F Jupiter" or "본관 3층 대회의실".
+
+type Room struct {
+	Name     string `json:"name"`
+	Building string `json:"building,omitempty"`
+	Floor    string `json:"floor,omitempty"`
+	Capacity int    `json:"capacity,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// OnSiteDay is a day with at least one meeting in a booked room.
+type OnSiteDay struct {
+	Date      string   `json:"date"`
+	Buildings []string `json:"buildings,omitempty"` // where the rooms are, when their names say
+	Meetings  int      `json:"meetings"`
+}
+
+var (
+	roomCapacityPattern = regexp.MustCompile(`\s*\((\d+)\)`)
+	roomFeaturesPattern = regexp.MustCompile(`\s*\[[^\]]*\]`)
+	// A Workspace floor segment: "3", "B1", "L2", "3F".
+	floorSegmentPattern = regexp.MustCompile(`(?i)^(B|L)?\d+F?$`)
+	// A floor written inside a name: "12F", "B1F", "3층", "Floor 4", "4th floor".
+	floorWordPattern = regexp.MustCompile(`(?i)\b(B?\d+\s*F|floor\s*\d+|\d+(st|nd|rd|th)\s+floor)\b|B?\d+\s*층`)
+)
+
+// parseRoomName splits a room's display name into building, floor, name and
+// capacity. Whatever it cannot place stays in the name.
+func parseRoomName(display string) Room {
+	var room Room
+	if m := roomCapacityPattern.FindStringSubmatch(display); m != nil {
+		room.Capacity, _ = strconv.Atoi(m[1])
+	}
+	name := roomFeaturesPattern.ReplaceAllString(roomCapacityPattern.ReplaceAllString(display, ""), "")
+	name = strings.TrimSpace(name)
+
+	if parts := strings.Split(name, "-"); len(parts) >= 3 && floorSegmentPattern.MatchString(strings.TrimSpace(parts[1])) {
+		room.Building = strings.TrimSpace(parts[0])
+		room.Floor = strings.TrimSpace(parts[1])
+		room.Name = strings.TrimSpace(strings.Join(parts[2:], "-"))
+		return room
+	}
+	if loc := floorWordPattern.FindStringIndex(name); loc != nil {
+		room.Building = strings.Trim(name[:loc[0]], " -/,")
+		room.Floor = strings.TrimSpace(name[loc[0]:loc[1]])
+		room.Name = strings.Trim(name[loc[1]:], " -/,")
+		if room.Name == "" {
+			room.Name = name
+		}
+		return room
+	}
+	room.Name = name
+	return room
+}
+
+// extractRooms lists the rooms booked for an event. Rooms that declined the
+// booking are left out: the meeting is not there.
+func extractRooms(event GogEvent) []Room {
+	var rooms []Room
+	for _, a := range event.Attendees {
+		if !a.Resource || a.ResponseStatus == "declined" {
+			continue
+		}
+		display := a.DisplayName
+		if display == "" {
+			display, _, _ = strings.Cut(a.Email, "@")
+		}
+		room := parseRoomName(display)
+		room.Email = a.Email
+		rooms = append(rooms, room)
+	}
+	return rooms
+}
+
+// findOnSiteDays lists the days, in loc, on which I have a meeting in a
+// booked room, so the brief can tell office days from remote ones.
+func findOnSiteDays(events []SimplifiedEvent, loc *time.Location) []OnSiteDay {
+	byDate := map[string]*OnSiteDay{}
+	var dates []string
+	for _, e := range events {
+		if e.Room == nil || e.Status == "cancelled" || e.Response == "declined" {
+			continue
+		}
+		start, err := time.Parse(time.RFC3339, e.Start)
+		if err != nil {
+			continue
+		}
+		date := start.In(loc).Format("2006-01-02")
+		d, ok := byDate[date]
+		if !ok {
+			d = &OnSiteDay{Date: date}
+			byDate[date] = d
+			dates = append(dates, date)
+		}
+		d.Meetings++
+		if b := e.Room.Building; b != "" && !containsString(d.Buildings, b) {
+			d.Buildings = append(d.Buildings, b)
+		}
+	}
+	sort.Strings(dates)
+	days := make([]OnSiteDay, 0, len(dates))
+	for _, date := range dates {
+		days = append(days, *byDate[date])
+	}
+	return days
+}
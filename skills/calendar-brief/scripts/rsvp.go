@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// --- RSVP ---
+
+// --accept, --decline and --tentative answer an invitation through gog. The
+// event is looked up on each account in turn; the first one that has it is
+// the one that answers, so pass --personal or --work when both are invited.
+
+type RSVPOutput struct {
+	EventID  string         `json:"event_id"`
+	Summary  string         `json:"summary,omitempty"`
+	Start    string         `json:"start,omitempty"`
+	Account  *Account       `json:"account,omitempty"`
+	Previous string         `json:"previous,omitempty"` // my response before this one
+	Response string         `json:"response"`           // accepted, declined or tentative
+	Done     bool           `json:"done"`
+	Staged   *PendingAction `json:"staged,omitempty"` // with --require-approval
+	Errors   []AccountError `json:"errors,omitempty"`
+}
+
+// fetchEvent reads one event of the account's primary calendar.
+func fetchEvent(accountEmail, eventID string) (GogEvent, error) {
+	// gog prints the event either bare or as {"event": {...}}.
+	var event struct {
+		GogEvent
+		Event *GogEvent `json:"event"`
+	}
+	err := streamGog(context.Background(), func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(&event)
+	}, "calendar", "event", "primary", eventID, "--json", fmt.Sprintf("--account=%s", accountEmail))
+	if err != nil {
+		return GogEvent{}, err
+	}
+	if event.Event != nil {
+		event.GogEvent = *event.Event
+	}
+	if event.ID == "" {
+		return GogEvent{}, fmt.Errorf("event %s not found", eventID)
+	}
+	return event.GogEvent, nil
+}
+
+// respondToEvent sets my responseStatus on an event and logs it for --undo.
+func respondToEvent(accountEmail, eventID, response, previous string) error {
+	err := streamGog(context.Background(), func(io.Reader) error { return nil },
+		"calendar", "respond", "primary", eventID,
+		fmt.Sprintf("--status=%s", response),
+		fmt.Sprintf("--account=%s", accountEmail))
+	recordAction("event_rsvp", accountEmail, map[string]string{
+		"event_id": eventID,
+		"response": response,
+		"previous": previous,
+	}, err)
+	return err
+}
+
+// runRSVP answers the invitation eventID with response. With stage set the
+// answer is queued for --approve instead.
+func runRSVP(accounts []Account, eventID, response string, stage bool) RSVPOutput {
+	output := RSVPOutput{EventID: eventID, Response: response}
+	fail := func(email string, err error) RSVPOutput {
+		output.Errors = append(output.Errors, AccountError{Email: email, Error: err.Error()})
+		return output
+	}
+
+	var event GogEvent
+	for i, account := range accounts {
+		e, err := fetchEvent(account.Email, eventID)
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			continue
+		}
+		event, output.Account, output.Errors = e, &accounts[i], nil
+		break
+	}
+	if output.Account == nil {
+		return output
+	}
+	account := *output.Account
+	output.Summary, output.Start = event.Summary, event.Start.value()
+	output.Previous = extractMyResponse(event)
+
+	if event.Organizer.Self {
+		return fail(account.Email, fmt.Errorf("%s is my own event; there is no invitation to answer", eventID))
+	}
+	if output.Previous == "" {
+		return fail(account.Email, fmt.Errorf("%s is not an invitation to %s", eventID, account.Email))
+	}
+	if stage {
+		p, err := stageAction("rsvp", account.Email, map[string]string{"event_id": eventID, "response": response, "previous": output.Previous})
+		if err != nil {
+			return fail(account.Email, err)
+		}
+		output.Staged = &p
+		return output
+	}
+	if err := respondToEvent(account.Email, eventID, response, output.Previous); err != nil {
+		return fail(account.Email, err)
+	}
+	output.Done = true
+	return output
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// --- Speech ---
+
+// --format speech reads the brief out as one short paragraph, for a voice
+// assistant or for reading aloud. Each locale has its own template; times
+// and durations are spelled the way they are said, not the way they are
+// written ("2:30 PM", "오후 2시 30분").
+
+// speechListMax is how many meetings after the first are named; the rest are
+// only counted.
+const speechListMax = 3
+
+type speechItem struct {
+	Summary string
+	At      string // "at 2 PM", or "on Monday at 2 PM" over several days
+	In      string // time until it starts, for the next meeting only
+}
+
+type speechData struct {
+	Period   string // "today", "tomorrow", "on Monday, October 19", ...
+	Count    int
+	First    *speechItem
+	Then     []speechItem
+	More     int
+	LastEnd  string // single days only
+	Overlaps int
+	AllDay   []string
+	Due      []string    // --tasks
+	Next     *speechItem // today's brief only
+}
+
+var speechTemplates = map[string]string{
+	"en": `
+{{- if eq .Count 0}}You have no meetings {{.Period}}.
+{{- else}}You have {{.Count}} meeting{{if ne .Count 1}}s{{end}} {{.Period}}, starting with {{.First.Summary}} {{.First.At}}.
+  {{- with .Then}} After that: {{range $i, $e := .}}{{if $i}}, {{end}}{{$e.Summary}} {{$e.At}}{{end}}{{if $.More}}, and {{$.More}} more{{end}}.{{end}}
+  {{- with .LastEnd}} Your last meeting ends at {{.}}.{{end}}
+  {{- if eq .Overlaps 1}} One pair of meetings overlaps.{{else if .Overlaps}} {{.Overlaps}} pairs of meetings overlap.{{end}}
+{{- end}}
+{{- with .AllDay}} All day: {{join ", " .}}.{{end}}
+{{- with .Due}} Due: {{join ", " .}}.{{end}}
+{{- with .Next}} Your next meeting, {{.Summary}}, starts in {{.In}}.{{end}}`,
+	"ko": `
+{{- if eq .Count 0}}{{.Period}} 회의가 없습니다.
+{{- else}}{{.Period}} 회의가 {{.Count}}개 있습니다. 첫 회의는 {{.First.At}} {{.First.Summary}}입니다.
+  {{- with .Then}} 이어서 {{range $i, $e := .}}{{if $i}}, {{end}}{{$e.At}} {{$e.Summary}}{{end}}{{if $.More}} 외 {{$.More}}개{{end}} 일정이 있습니다.{{end}}
+  {{- with .LastEnd}} 마지막 회의는 {{.}}에 끝납니다.{{end}}
+  {{- if .Overlaps}} 겹치는 회의가 {{.Overlaps}}건 있습니다.{{end}}
+{{- end}}
+{{- with .AllDay}} 종일 일정: {{join ", " .}}.{{end}}
+{{- with .Due}} 마감: {{join ", " .}}.{{end}}
+{{- with .Next}} 다음 회의 {{.Summary}}까지 {{.In}} 남았습니다.{{end}}`,
+}
+
+var koreanWeekdayNames = []string{"일요일", "월요일", "화요일", "수요일", "목요일", "금요일", "토요일"}
+
+// speakClock says a time of day: "9 AM", "2:30 PM" or "오전 9시", "오후 2시 30분".
+func speakClock(locale string, t time.Time) string {
+	if locale == "ko" {
+		half, h := "오전", t.Hour()
+		if h >= 12 {
+			half, h = "오후", h-12
+		}
+		if h == 0 {
+			h = 12
+		}
+		if t.Minute() == 0 {
+			return fmt.Sprintf("%s %d시", half, h)
+		}
+		return fmt.Sprintf("%s %d시 %d분", half, h, t.Minute())
+	}
+	if t.Minute() == 0 {
+		return t.Format("3 PM")
+	}
+	return t.Format("3:04 PM")
+}
+
+// speakMinutes says a duration: "25 minutes", "1 hour 5 minutes", "1시간 5분".
+func speakMinutes(locale string, minutes int) string {
+	if locale == "ko" {
+		return humanizeMinutes(locale, minutes)
+	}
+	unit := func(n int, name string) string {
+		if n == 1 {
+			return "1 " + name
+		}
+		return fmt.Sprintf("%d %ss", n, name)
+	}
+	h, m := minutes/60, minutes%60
+	switch {
+	case h == 0:
+		return unit(m, "minute")
+	case m == 0:
+		return unit(h, "hour")
+	}
+	return unit(h, "hour") + " " + unit(m, "minute")
+}
+
+// speakPeriod names the brief's range relative to now.
+func speakPeriod(locale string, from, to, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, from.Location())
+	last := to.AddDate(0, 0, -1)
+	ko := locale == "ko"
+	switch {
+	case from.Equal(today) && to.Equal(today.AddDate(0, 0, 1)):
+		if ko {
+			return "오늘"
+		}
+		return "today"
+	case from.Equal(today.AddDate(0, 0, 1)) && to.Equal(today.AddDate(0, 0, 2)):
+		if ko {
+			return "내일"
+		}
+		return "tomorrow"
+	case from.AddDate(0, 0, 1).Equal(to):
+		if ko {
+			return fmt.Sprintf("%d월 %d일 %s", from.Month(), from.Day(), koreanWeekdayNames[from.Weekday()])
+		}
+		return from.Format("on Monday, January 2")
+	}
+	if ko {
+		return fmt.Sprintf("%d월 %d일부터 %d월 %d일까지", from.Month(), from.Day(), last.Month(), last.Day())
+	}
+	return fmt.Sprintf("between %s and %s", from.Format("January 2"), last.Format("January 2"))
+}
+
+// writeSpeech renders output as a spoken paragraph for [from, to).
+func writeSpeech(w io.Writer, output Output, from, to, now time.Time, loc *time.Location, locale string) error {
+	multiDay := !from.AddDate(0, 0, 1).Equal(to)
+	at := func(t time.Time) string {
+		t = t.In(loc)
+		switch {
+		case locale == "ko" && multiDay:
+			return koreanWeekdayNames[t.Weekday()] + " " + speakClock(locale, t)
+		case locale == "ko":
+			return speakClock(locale, t)
+		case multiDay:
+			return t.Format("on Monday") + " at " + speakClock(locale, t)
+		}
+		return "at " + speakClock(locale, t)
+	}
+
+	data := speechData{Period: speakPeriod(locale, from, to, now), Overlaps: len(output.Conflicts)}
+	var lastEnd time.Time
+	for _, e := range output.Events {
+		if e.Type == "task" {
+			data.Due = append(data.Due, e.Summary)
+			continue
+		}
+		if e.AllDay {
+			if e.Status != "cancelled" && e.Response != "declined" && e.EventType != "workingLocation" {
+				data.AllDay = append(data.AllDay, e.Summary)
+			}
+			continue
+		}
+		start, end, ok := blocksTime(e)
+		if !ok {
+			continue
+		}
+		data.Count++
+		item := speechItem{Summary: e.Summary, At: at(start)}
+		switch {
+		case data.First == nil:
+			data.First = &item
+		case len(data.Then) < speechListMax:
+			data.Then = append(data.Then, item)
+		default:
+			data.More++
+		}
+		if end.After(lastEnd) {
+			lastEnd = end
+		}
+		if data.Next == nil && e.StartsInMinutes != nil && *e.StartsInMinutes >= 0 {
+			data.Next = &speechItem{Summary: e.Summary, At: item.At, In: speakMinutes(locale, *e.StartsInMinutes)}
+		}
+	}
+	if data.Count > 0 && !multiDay {
+		data.LastEnd = speakClock(locale, lastEnd.In(loc))
+	}
+
+	tmpl, err := template.New("speech").Funcs(templateFuncs(loc, locale, now)).Parse(speechTemplates[locale])
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, strings.Join(strings.Fields(b.String()), " "))
+	return err
+}
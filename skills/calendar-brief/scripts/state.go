@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// --- Local State ---
+
+// statePath returns the location of a state file under the user cache
+// directory (~/.cache/calendar-brief on Linux, ~/Library/Caches/calendar-brief
+// on macOS).
+func statePath(name string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "calendar-brief", name), nil
+}
+
+// loadState decodes a state file into v. A missing or unreadable file leaves
+// v untouched and reports false.
+func loadState(name string, v interface{}) bool {
+	path, err := statePath(name)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func saveState(name string, v interface{}) error {
+	path, err := statePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// --- Read-only Mode ---
+
+// readOnlyEnv turns on --read-only for every run, for users who never want
+// the tools to write.
+const readOnlyEnv = "BRIEF_READ_ONLY"
+
+func readOnlyFromEnv() bool {
+	v := strings.ToLower(os.Getenv(readOnlyEnv))
+	return v != "" && v != "0" && v != "false"
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// --- Week Stats ---
+
+type WeekStats struct {
+	MeetingCount     int        `json:"meeting_count"`
+	MeetingHours     float64    `json:"meeting_hours"`
+	OneOnOnes        int        `json:"one_on_ones"`
+	PerDay           []DayStats `json:"per_day"`
+	BusiestDay       string     `json:"busiest_day,omitempty"`
+	LongestFreeBlock *FreeSlot  `json:"longest_free_block,omitempty"`
+	BookedPercent    float64    `json:"booked_percent"` // of weekday working hours
+
+	LongestStreakMinutes int `json:"longest_streak_minutes"` // back-to-back meetings, see markBackToBack
+	OutsideHoursMinutes  int `json:"outside_hours_minutes"`  // meeting time outside each account's working hours
+	NoAgendaMeetings     int `json:"no_agenda_meetings"`     // see lacksAgenda
+
+	Chart string `json:"chart,omitempty"` // SVG written by --chart
+
+	Cost *MeetingCost `json:"cost,omitempty"` // with --cost
+
+	Compare *StatsComparison `json:"compare,omitempty"` // with --compare
+}
+
+type DayStats struct {
+	Date           string `json:"date"`
+	Meetings       int    `json:"meetings"`
+	MeetingMinutes int    `json:"meeting_minutes"`
+}
+
+func round1(f float64) float64 {
+	return math.Round(f*10) / 10
+}
+
+// computeWeekStats summarizes how heavy [from, to) is. Meetings are the events
+// that block time (see blocksTime); booked time and free blocks only count
+// weekday working hours, and overlapping meetings are not double-counted.
+func computeWeekStats(events []SimplifiedEvent, from, to time.Time, dayStart, dayEnd time.Duration) WeekStats {
+	stats := WeekStats{PerDay: []DayStats{}}
+	byDay := map[string]*DayStats{}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		stats.PerDay = append(stats.PerDay, DayStats{Date: day.Format("2006-01-02")})
+	}
+	for i := range stats.PerDay {
+		byDay[stats.PerDay[i].Date] = &stats.PerDay[i]
+	}
+
+	var total time.Duration
+	for _, e := range events {
+		start, end, ok := blocksTime(e)
+		if !ok || !start.Before(to) || !end.After(from) {
+			continue
+		}
+		stats.MeetingCount++
+		if e.Size == "one_on_one" {
+			stats.OneOnOnes++
+		}
+		if e.NoAgenda {
+			stats.NoAgendaMeetings++
+		}
+		total += end.Sub(start)
+		if d, ok := byDay[start.In(from.Location()).Format("2006-01-02")]; ok {
+			d.Meetings++
+			d.MeetingMinutes += int(end.Sub(start).Minutes())
+		}
+	}
+	stats.MeetingHours = round1(total.Hours())
+
+	busiest := 0
+	for _, d := range stats.PerDay {
+		if d.MeetingMinutes > busiest {
+			busiest, stats.BusiestDay = d.MeetingMinutes, d.Date
+		}
+	}
+
+	busy := mergeBusy(events)
+	var working, booked time.Duration
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !isBusinessDay(day, nil) {
+			continue
+		}
+		winStart, winEnd := day.Add(dayStart), day.Add(dayEnd)
+		working += winEnd.Sub(winStart)
+		cursor := winStart
+		gap := func(gapEnd time.Time) {
+			if gapEnd.After(cursor) && (stats.LongestFreeBlock == nil || int(gapEnd.Sub(cursor).Minutes()) > stats.LongestFreeBlock.Minutes) {
+				stats.LongestFreeBlock = &FreeSlot{
+					Start:   cursor.Format(time.RFC3339),
+					End:     gapEnd.Format(time.RFC3339),
+					Minutes: int(gapEnd.Sub(cursor).Minutes()),
+				}
+			}
+		}
+		for _, b := range busy {
+			s, e := b.start, b.end
+			if !e.After(winStart) || !s.Before(winEnd) {
+				continue
+			}
+			if s.Before(winStart) {
+				s = winStart
+			}
+			if e.After(winEnd) {
+				e = winEnd
+			}
+			booked += e.Sub(s)
+			if s.After(cursor) {
+				gap(s)
+			}
+			if e.After(cursor) {
+				cursor = e
+			}
+		}
+		gap(winEnd)
+	}
+	if working > 0 {
+		stats.BookedPercent = round1(100 * booked.Seconds() / working.Seconds())
+	}
+	return stats
+}
+
+// meetingHoursChart charts meeting hours per day of the week.
+func meetingHoursChart(stats WeekStats, loc *time.Location) string {
+	labels := make([]string, len(stats.PerDay))
+	values := make([]float64, len(stats.PerDay))
+	for i, d := range stats.PerDay {
+		labels[i] = d.Date
+		if t, err := time.ParseInLocation("2006-01-02", d.Date, loc); err == nil {
+			labels[i] = t.Format("Mon 1/2")
+		}
+		values[i] = round1(float64(d.MeetingMinutes) / 60)
+	}
+	return barChartSVG("Meeting hours per day", labels, values, "h")
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// --- Streaming Decode ---
+
+// A month of events comes back from gog as one large JSON document. Rather
+// than buffering it and unmarshalling the whole tree at once, gog's stdout
+// is read through a json.Decoder and the list is decoded one item at a time.
+
+var errUnexpectedJSON = errors.New("unexpected JSON format from gog")
+
+// gogList is gog's reply to a list command: {"<key>": [...],
+// "nextPageToken": "..."} or a bare array.
+type gogList[T any] struct {
+	Items         []T
+	NextPageToken string
+	// Malformed counts the items that did not fit T. An object with a field
+	// of the wrong type is kept with that field left empty; anything else
+	// (null, a string) is skipped.
+	Malformed int
+}
+
+// decodeList streams a gogList from r. Members other than key and
+// nextPageToken are skipped, and an object without key yields no items.
+func decodeList[T any](r io.Reader, key string) (gogList[T], error) {
+	var list gogList[T]
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return list, errUnexpectedJSON
+	}
+	switch tok {
+	case json.Delim('['):
+		list.Items, list.Malformed, err = decodeItems[T](dec)
+		return list, err
+	case json.Delim('{'):
+	default:
+		return list, errUnexpectedJSON
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return list, errUnexpectedJSON
+		}
+		switch name, _ := tok.(string); name {
+		case key:
+			if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+				return list, errUnexpectedJSON
+			}
+			if list.Items, list.Malformed, err = decodeItems[T](dec); err != nil {
+				return list, err
+			}
+		case "nextPageToken":
+			var next interface{}
+			if err := dec.Decode(&next); err != nil {
+				return list, errUnexpectedJSON
+			}
+			list.NextPageToken, _ = next.(string)
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return list, errUnexpectedJSON
+			}
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return list, errUnexpectedJSON
+	}
+	return list, nil
+}
+
+// decodeItems reads array elements up to and including the closing bracket
+// and counts the malformed ones. json.Unmarshal fills in every field it can
+// before reporting a type mismatch, so such an item is kept rather than lost
+// over one odd field.
+func decodeItems[T any](dec *json.Decoder) ([]T, int, error) {
+	items := []T{}
+	malformed := 0
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, 0, errUnexpectedJSON
+		}
+		if len(raw) == 0 || raw[0] != '{' {
+			malformed++
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(raw, &item); err != nil {
+			malformed++
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				continue
+			}
+		}
+		items = append(items, item)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, 0, errUnexpectedJSON
+	}
+	return items, malformed, nil
+}
+
+// streamGog runs gog once a gogPool slot is free and hands its stdout to
+// decode as it is produced. The call is capped at 30s from the moment gog
+// starts. A failed gog run is reported with its stderr in preference to
+// whatever decode made of the partial output.
+func streamGog(ctx context.Context, decode func(io.Reader) error, args ...string) error {
+	release, err := gogPool.acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "gog", args...)
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	decodeErr := decode(stdout)
+	io.Copy(io.Discard, stdout) // let gog finish writing
+	if err := cmd.Wait(); err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = fmt.Sprintf("gog exited with code %d", cmd.ProcessState.ExitCode())
+		}
+		return fmt.Errorf("%s", errMsg)
+	}
+	return decodeErr
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// --- Google Tasks ---
+
+// --tasks lists open Google Tasks due in the brief's range next to the
+// events, so deadlines show up in the same day as the meetings. Tasks only
+// carry a due date, so they come through as all-day entries with
+// type "task"; they never block time.
+
+// GogTask is a Google Task as gog prints it.
+type GogTask struct {
+	ID          string `json:"id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Notes       string `json:"notes,omitempty"`
+	Status      string `json:"status,omitempty"` // needsAction or completed
+	Due         string `json:"due,omitempty"`    // RFC 3339, but only the date is meaningful
+	WebViewLink string `json:"webViewLink,omitempty"`
+}
+
+// fetchTasks reads the open tasks of the account's default list that are
+// due in [from, to).
+func fetchTasks(accountEmail string, from, to time.Time) ([]GogTask, error) {
+	var list gogList[GogTask]
+	err := streamGog(context.Background(), func(r io.Reader) error {
+		var err error
+		list, err = decodeList[GogTask](r, "tasks")
+		return err
+	}, "tasks", "list", "@default", "--json",
+		fmt.Sprintf("--due-min=%s", from.Format(time.RFC3339)),
+		fmt.Sprintf("--due-max=%s", to.Format(time.RFC3339)),
+		fmt.Sprintf("--account=%s", accountEmail))
+	if err != nil {
+		return nil, err
+	}
+
+	first, last := from.Format("2006-01-02"), to.Add(-time.Nanosecond).Format("2006-01-02")
+	var tasks []GogTask
+	for _, t := range list.Items {
+		if t.Status == "completed" || len(t.Due) < len("2006-01-02") {
+			continue
+		}
+		// The due date is stored as midnight UTC whatever the timezone.
+		if due := t.Due[:10]; due >= first && due <= last {
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks, nil
+}
+
+// taskToEvent turns a task into an all-day entry on its due date.
+func taskToEvent(t GogTask, account Account, description int) SimplifiedEvent {
+	due := t.Due[:10]
+	next := due
+	if d, err := time.Parse("2006-01-02", due); err == nil {
+		next = d.AddDate(0, 0, 1).Format("2006-01-02")
+	}
+	title := t.Title
+	if title == "" {
+		title = "(No title)"
+	}
+	e := SimplifiedEvent{
+		Type:        "task",
+		EventID:     t.ID,
+		HTMLLink:    t.WebViewLink,
+		Summary:     title,
+		Start:       due,
+		End:         next,
+		AllDay:      true,
+		Status:      t.Status,
+		EventType:   "default",
+		AccountType: account.Type,
+		account:     account.Email,
+		calendar:    account.Email + ":tasks",
+	}
+	if description > 0 {
+		e.Description = plainDescription(t.Notes, description)
+	}
+	return e
+}
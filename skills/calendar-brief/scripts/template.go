@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// --- Template Rendering ---
+
+var koreanWeekdays = []string{"일", "월", "화", "수", "목", "금", "토"}
+
+var responseEmoji = map[string]string{
+	"accepted":    "✅",
+	"tentative":   "❔",
+	"declined":    "❌",
+	"needsAction": "⏳",
+}
+
+// parseEventTime reads an event start/end: RFC 3339 for timed events, a bare
+// date (midnight in loc) for all-day ones.
+func parseEventTime(value string, loc *time.Location) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.In(loc), true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// templateLabels are the section headers and stat labels for {{label "key"}},
+// so one template file serves readers in either locale.
+var templateLabels = map[string]map[string]string{
+	"en": {
+		"schedule":  "Schedule",
+		"all_day":   "All day",
+		"conflicts": "Conflicts",
+		"free":      "Free time",
+		"tasks":     "Tasks",
+		"meetings":  "Meetings",
+		"events":    "Events",
+		"no_events": "No events",
+		"location":  "Location",
+		"attendees": "Attendees",
+		"organizer": "Organizer",
+		"busy":      "Busy",
+		"focus":     "Focus time",
+	},
+	"ko": {
+		"schedule":  "일정",
+		"all_day":   "종일",
+		"conflicts": "겹치는 일정",
+		"free":      "빈 시간",
+		"tasks":     "할 일",
+		"meetings":  "회의",
+		"events":    "일정",
+		"no_events": "일정 없음",
+		"location":  "장소",
+		"attendees": "참석자",
+		"organizer": "주최자",
+		"busy":      "바쁨",
+		"focus":     "집중 시간",
+	},
+}
+
+// humanizeMinutes renders a duration as "45m", "2h" or "1h 30m" (ko: "45분",
+// "2시간", "1시간 30분").
+func humanizeMinutes(locale string, minutes int) string {
+	h, m := minutes/60, minutes%60
+	hu, mu := "h", "m"
+	if locale == "ko" {
+		hu, mu = "시간", "분"
+	}
+	switch {
+	case h == 0:
+		return fmt.Sprintf("%d%s", m, mu)
+	case m == 0:
+		return fmt.Sprintf("%d%s", h, hu)
+	}
+	return fmt.Sprintf("%d%s %d%s", h, hu, m, mu)
+}
+
+// relativeTime says when t is relative to now: "in 25m", "10m ago",
+// "tomorrow", "in 3 days" (ko: "25분 후", "10분 전", "내일", "3일 후").
+// Within today it counts minutes; otherwise it counts calendar days in loc.
+func relativeTime(locale string, t, now time.Time, loc *time.Location) string {
+	ko := locale == "ko"
+	t, now = t.In(loc), now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	days := int(math.Round(day.Sub(today).Hours() / 24))
+	if days == 0 {
+		minutes := int(t.Sub(now).Round(time.Minute).Minutes())
+		switch {
+		case minutes == 0 && ko:
+			return "지금"
+		case minutes == 0:
+			return "now"
+		case minutes > 0 && ko:
+			return humanizeMinutes(locale, minutes) + " 후"
+		case minutes > 0:
+			return "in " + humanizeMinutes(locale, minutes)
+		case ko:
+			return humanizeMinutes(locale, -minutes) + " 전"
+		}
+		return humanizeMinutes(locale, -minutes) + " ago"
+	}
+	switch {
+	case days == 1 && ko:
+		return "내일"
+	case days == 1:
+		return "tomorrow"
+	case days == -1 && ko:
+		return "어제"
+	case days == -1:
+		return "yesterday"
+	case days > 0 && ko:
+		return fmt.Sprintf("%d일 후", days)
+	case days > 0:
+		return fmt.Sprintf("in %d days", days)
+	case ko:
+		return fmt.Sprintf("%d일 전", -days)
+	}
+	return fmt.Sprintf("%d days ago", -days)
+}
+
+// groupHeading renders a groupEvents key as a section header: days as the
+// "day" function does, ISO weeks as "Week 42" (ko: "42주차"); account and
+// calendar keys are kept as they are.
+func groupHeading(locale, key string, loc *time.Location) string {
+	var year, week int
+	if n, _ := fmt.Sscanf(key, "%d-W%d", &year, &week); n == 2 {
+		if locale == "ko" {
+			return fmt.Sprintf("%d주차", week)
+		}
+		return fmt.Sprintf("Week %d", week)
+	}
+	if t, err := time.ParseInLocation("2006-01-02", key, loc); err == nil {
+		return localDay(locale, t)
+	}
+	return key
+}
+
+// localDay renders a date as "Sat, Oct 17" (ko: "10월 17일 (토)").
+func localDay(locale string, t time.Time) string {
+	if locale == "ko" {
+		return fmt.Sprintf("%d월 %d일 (%s)", t.Month(), t.Day(), koreanWeekdays[t.Weekday()])
+	}
+	return t.Format("Mon, Jan 2")
+}
+
+// templateFuncs is the function library available to --template files.
+// Times are shown in loc; locale is "en" or "ko"; relative phrases count
+// from now.
+func templateFuncs(loc *time.Location, locale string, now time.Time) template.FuncMap {
+	return template.FuncMap{
+		// time formats an event time with a Go layout, e.g. {{time "15:04" .Start}}.
+		"time": func(layout, value string) string {
+			if t, ok := parseEventTime(value, loc); ok {
+				return t.Format(layout)
+			}
+			return value
+		},
+		// day renders the date in the locale: "Sat, Oct 17" or "10월 17일 (토)".
+		"day": func(value string) string {
+			if t, ok := parseEventTime(value, loc); ok {
+				return localDay(locale, t)
+			}
+			return value
+		},
+		// relative says when an event time is from now: "in 25m", "tomorrow".
+		"relative": func(value string) string {
+			if t, ok := parseEventTime(value, loc); ok {
+				return relativeTime(locale, t, now, loc)
+			}
+			return value
+		},
+		// heading renders a group key from groupBy as a section header.
+		"heading":  func(key string) string { return groupHeading(locale, key, loc) },
+		"label":    func(key string) string { return localLabel(locale, key) },
+		"duration": func(minutes int) string { return humanizeMinutes(locale, minutes) },
+		"truncate": func(n int, s string) string {
+			if r := []rune(s); len(r) > n {
+				return string(r[:n]) + "…"
+			}
+			return s
+		},
+		"groupBy": func(by string, events []SimplifiedEvent) []EventGroup {
+			return groupEvents(events, by, loc)
+		},
+		"emoji": func(response string) string { return responseEmoji[response] },
+		"join":  func(sep string, items []string) string { return strings.Join(items, sep) },
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+	}
+}
+
+// localLabel looks up a templateLabels key, falling back to English and then
+// to the key itself.
+func localLabel(locale, key string) string {
+	if label, ok := templateLabels[locale][key]; ok {
+		return label
+	}
+	if label, ok := templateLabels["en"][key]; ok {
+		return label
+	}
+	return key
+}
+
+// renderTemplate executes the text/template at path against data.
+func renderTemplate(path string, data interface{}, loc *time.Location, locale string, now time.Time) error {
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(loc, locale, now)).ParseFiles(path)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(os.Stdout, data)
+}
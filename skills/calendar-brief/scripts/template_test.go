@@ -0,0 +1,93 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Seoul")
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, loc)
+	cases := []struct {
+		at     time.Time
+		en, ko string
+	}{
+		{now, "now", "지금"},
+		{now.Add(25 * time.Minute), "in 25m", "25분 후"},
+		{now.Add(90 * time.Minute), "in 1h 30m", "1시간 30분 후"},
+		{now.Add(-10 * time.Minute), "10m ago", "10분 전"},
+		{time.Date(2026, 10, 18, 8, 0, 0, 0, loc), "tomorrow", "내일"},
+		{time.Date(2026, 10, 16, 23, 0, 0, 0, loc), "yesterday", "어제"},
+		{time.Date(2026, 10, 20, 0, 0, 0, 0, loc), "in 3 days", "3일 후"},
+		{time.Date(2026, 10, 12, 0, 0, 0, 0, loc), "5 days ago", "5일 전"},
+	}
+	for _, c := range cases {
+		if got := relativeTime("en", c.at, now, loc); got != c.en {
+			t.Errorf("en %v: got %q, want %q", c.at, got, c.en)
+		}
+		if got := relativeTime("ko", c.at, now, loc); got != c.ko {
+			t.Errorf("ko %v: got %q, want %q", c.at, got, c.ko)
+		}
+	}
+}
+
+func TestGroupHeading(t *testing.T) {
+	cases := []struct{ key, en, ko string }{
+		{"2026-10-17", "Sat, Oct 17", "10월 17일 (토)"},
+		{"2026-W42", "Week 42", "42주차"},
+		{"me@example.com", "me@example.com", "me@example.com"},
+	}
+	for _, c := range cases {
+		if got := groupHeading("en", c.key, time.UTC); got != c.en {
+			t.Errorf("en %q: got %q, want %q", c.key, got, c.en)
+		}
+		if got := groupHeading("ko", c.key, time.UTC); got != c.ko {
+			t.Errorf("ko %q: got %q, want %q", c.key, got, c.ko)
+		}
+	}
+}
+
+func TestTemplateLabelsCoverBothLocales(t *testing.T) {
+	for key := range templateLabels["en"] {
+		if _, ok := templateLabels["ko"][key]; !ok {
+			t.Errorf("label %q has no ko translation", key)
+		}
+	}
+	if got := localLabel("ko", "missing"); got != "missing" {
+		t.Errorf("unknown label: got %q", got)
+	}
+}
+
+func TestRenderTemplateLocale(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), "brief.tmpl")
+	body := `{{label "schedule"}}{{range groupBy "day" .Events}} [{{heading .Key}}]{{range .Events}} {{.Summary}} {{relative .Start}}{{end}}{{end}}`
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	output := Output{Events: []SimplifiedEvent{
+		{Summary: "Standup", Start: "2026-10-17T09:30:00Z"},
+		{Summary: "Review", Start: "2026-10-18T14:00:00Z"},
+	}}
+	want := map[string]string{
+		"en": "Schedule [Sat, Oct 17] Standup in 30m [Sun, Oct 18] Review tomorrow",
+		"ko": "일정 [10월 17일 (토)] Standup 30분 후 [10월 18일 (일)] Review 내일",
+	}
+	for locale, w := range want {
+		tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs(time.UTC, locale, now)).ParseFiles(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		if err := tmpl.Execute(&b, output); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != w {
+			t.Errorf("%s: got %q, want %q", locale, b.String(), w)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// --- Timeline ---
+
+// TimelineDay lays one day out as alternating busy and free blocks, merged
+// across accounts. The day runs over working hours, stretched to cover any
+// meeting that starts earlier or ends later.
+type TimelineDay struct {
+	Date        string          `json:"date"`
+	Blocks      []TimelineBlock `json:"blocks"`
+	BusyMinutes int             `json:"busy_minutes"`
+	FreeMinutes int             `json:"free_minutes"`
+}
+
+type TimelineBlock struct {
+	State   string   `json:"state"` // busy or free
+	Start   string   `json:"start"`
+	End     string   `json:"end"`
+	Minutes int      `json:"minutes"`
+	Events  []string `json:"events,omitempty"` // summaries of the events making a busy block
+}
+
+// busyBlock is a merged busy interval with the events that fill it.
+type busyBlock struct {
+	interval
+	summaries []string
+}
+
+// mergeBusyBlocks is mergeBusy that remembers which events each interval
+// came from.
+func mergeBusyBlocks(events []SimplifiedEvent) []busyBlock {
+	var busy []busyBlock
+	for _, e := range events {
+		if start, end, ok := blocksTime(e); ok {
+			busy = append(busy, busyBlock{interval{start, end}, []string{e.Summary}})
+		}
+	}
+	sort.SliceStable(busy, func(i, j int) bool { return busy[i].start.Before(busy[j].start) })
+
+	merged := make([]busyBlock, 0, len(busy))
+	for _, b := range busy {
+		if n := len(merged); n > 0 && !b.start.After(merged[n-1].end) {
+			if b.end.After(merged[n-1].end) {
+				merged[n-1].end = b.end
+			}
+			merged[n-1].summaries = append(merged[n-1].summaries, b.summaries...)
+			continue
+		}
+		merged = append(merged, b)
+	}
+	return merged
+}
+
+// buildTimeline returns a TimelineDay for every day that [from, to) touches.
+func buildTimeline(events []SimplifiedEvent, from, to time.Time, dayStart, dayEnd time.Duration) []TimelineDay {
+	busy := mergeBusyBlocks(events)
+	days := []TimelineDay{}
+
+	first := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	for day := first; day.Before(to); day = day.AddDate(0, 0, 1) {
+		midnight, next := day, day.AddDate(0, 0, 1)
+		start, end := day.Add(dayStart), day.Add(dayEnd)
+		var today []busyBlock
+		for _, b := range busy {
+			if !b.end.After(midnight) || !b.start.Before(next) {
+				continue
+			}
+			b.interval = interval{laterOf(b.start, midnight), earlierOf(b.end, next)}
+			if b.start.Before(start) {
+				start = b.start
+			}
+			if b.end.After(end) {
+				end = b.end
+			}
+			today = append(today, b)
+		}
+
+		td := TimelineDay{Date: day.Format("2006-01-02"), Blocks: []TimelineBlock{}}
+		add := func(state string, s, e time.Time, summaries []string) {
+			if !e.After(s) {
+				return
+			}
+			minutes := int(e.Sub(s).Minutes())
+			td.Blocks = append(td.Blocks, TimelineBlock{
+				State:   state,
+				Start:   s.In(day.Location()).Format(time.RFC3339),
+				End:     e.In(day.Location()).Format(time.RFC3339),
+				Minutes: minutes,
+				Events:  summaries,
+			})
+			if state == "busy" {
+				td.BusyMinutes += minutes
+			} else {
+				td.FreeMinutes += minutes
+			}
+		}
+		cursor := start
+		for _, b := range today {
+			add("free", cursor, b.start, nil)
+			add("busy", b.start, b.end, b.summaries)
+			cursor = b.end
+		}
+		add("free", cursor, end, nil)
+		days = append(days, td)
+	}
+	return days
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+func earlierOf(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
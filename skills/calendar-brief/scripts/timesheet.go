@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Time Tracking Export ---
+
+// googleColors names Google Calendar's event colorId values.
+var googleColors = map[string]string{
+	"1": "lavender", "2": "sage", "3": "grape", "4": "flamingo", "5": "banana", "6": "tangerine",
+	"7": "peacock", "8": "graphite", "9": "blueberry", "10": "basil", "11": "tomato",
+}
+
+// categoryPattern reads a category from a "[Client] ..." or "Client: ..."
+// title prefix.
+var categoryPattern = regexp.MustCompile(`^\s*(?:\[([^\]]+)\]|([^:\[\]]{1,40}):\s)`)
+
+func eventCategory(summary string) string {
+	if m := categoryPattern.FindStringSubmatch(summary); m != nil {
+		return strings.TrimSpace(m[1] + m[2])
+	}
+	return "uncategorized"
+}
+
+// attendeeDomain is the most common email domain among the other attendees
+// that differs from mine, i.e. the client or partner the meeting is with.
+func attendeeDomain(attendees []Attendee) string {
+	mine := ""
+	for _, a := range attendees {
+		if a.Self {
+			mine = emailDomain(a.Email)
+		}
+	}
+	counts := map[string]int{}
+	best := ""
+	for _, a := range attendees {
+		d := emailDomain(a.Email)
+		if a.Self || d == "" || d == mine {
+			continue
+		}
+		counts[d]++
+		if best == "" || counts[d] > counts[best] {
+			best = d
+		}
+	}
+	if best == "" {
+		return "internal"
+	}
+	return best
+}
+
+func trackGroup(e SimplifiedEvent, by string) string {
+	switch by {
+	case "color":
+		if name, ok := googleColors[e.colorID]; ok {
+			return name
+		}
+		return "default"
+	case "domain":
+		return e.attendeeDomain
+	}
+	return eventCategory(e.Summary)
+}
+
+// writeTimesheet writes the meetings that have already ended as CSV
+// time-tracking entries, grouped by category, color or attendee domain and
+// sorted by group, then start.
+func writeTimesheet(events []SimplifiedEvent, by string, now time.Time, loc *time.Location) error {
+	type entry struct {
+		group      string
+		start, end time.Time
+		e          SimplifiedEvent
+	}
+	var entries []entry
+	for _, e := range events {
+		start, end, ok := blocksTime(e)
+		if !ok || end.After(now) {
+			continue
+		}
+		entries = append(entries, entry{trackGroup(e, by), start.In(loc), end.In(loc), e})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].group != entries[j].group {
+			return entries[i].group < entries[j].group
+		}
+		return entries[i].start.Before(entries[j].start)
+	})
+
+	w := csv.NewWriter(os.Stdout)
+	w.Write([]string{by, "date", "start", "end", "hours", "summary", "account"})
+	for _, en := range entries {
+		w.Write([]string{
+			en.group,
+			en.start.Format("2006-01-02"),
+			en.start.Format("15:04"),
+			en.end.Format("15:04"),
+			strconv.FormatFloat(en.end.Sub(en.start).Hours(), 'f', 2, 64),
+			en.e.Summary,
+			en.e.account,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("writing CSV: %v", err)
+	}
+	return nil
+}
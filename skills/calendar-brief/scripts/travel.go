@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Travel Time ---
+
+// virtualLocationPattern matches locations that are not a place to travel to.
+var virtualLocationPattern = regexp.MustCompile(`(?i)https?://|\b(zoom|google meet|meet\.google|teams|webex|online|virtual|remote|tbd|phone)\b|온라인|화상|전화`)
+
+func isPhysicalLocation(location string) bool {
+	return strings.TrimSpace(location) != "" && !virtualLocationPattern.MatchString(location)
+}
+
+// travelEstimator returns the minutes needed to get from one location to
+// another.
+type travelEstimator func(from, to string) int
+
+// newTravelEstimator uses the configured travel_command when there is one
+// (run through `sh -c` with TRAVEL_FROM and TRAVEL_TO set; it prints the
+// minutes), falling back to the flat buffer when it fails or is not set.
+// Answers are cached per pair for the run.
+func newTravelEstimator(command string, buffer int) travelEstimator {
+	cache := map[[2]string]int{}
+	return func(from, to string) int {
+		if command == "" {
+			return buffer
+		}
+		key := [2]string{from, to}
+		if minutes, ok := cache[key]; ok {
+			return minutes
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		cmd := exec.CommandContext(ctx, "sh", "-c", command)
+		cmd.Env = append(os.Environ(), "TRAVEL_FROM="+from, "TRAVEL_TO="+to)
+		minutes := buffer
+		if out, err := cmd.Output(); err == nil {
+			if n, err := strconv.Atoi(strings.TrimSpace(string(out))); err == nil && n >= 0 {
+				minutes = n
+			}
+		}
+		cache[key] = minutes
+		return minutes
+	}
+}
+
+// markTravelWarnings sets TravelWarning on each meeting that starts too soon
+// after the previous meeting at a different physical place. Meetings without
+// a physical location (video calls, no location) don't move you, so they are
+// skipped when pairing.
+func markTravelWarnings(events []SimplifiedEvent, estimate travelEstimator) {
+	type meeting struct {
+		index      int
+		start, end time.Time
+	}
+	var meetings []meeting
+	for i, e := range events {
+		if start, end, ok := blocksTime(e); ok && isPhysicalLocation(e.Location) {
+			meetings = append(meetings, meeting{i, start, end})
+		}
+	}
+	sort.SliceStable(meetings, func(i, j int) bool { return meetings[i].start.Before(meetings[j].start) })
+
+	for k := 1; k < len(meetings); k++ {
+		prev, next := events[meetings[k-1].index], &events[meetings[k].index]
+		from, to := strings.TrimSpace(prev.Location), strings.TrimSpace(next.Location)
+		if strings.EqualFold(from, to) {
+			continue
+		}
+		gap := meetings[k].start.Sub(meetings[k-1].end)
+		if gap >= 12*time.Hour {
+			continue
+		}
+		if gap < 0 {
+			gap = 0
+		}
+		if need := estimate(from, to); int(gap.Minutes()) < need {
+			next.TravelWarning = fmt.Sprintf("starts %dm after %q at %s; travel from there takes about %dm", int(gap.Minutes()), prev.Summary, from, need)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// --- Week Start ---
+
+// weekStartEnv sets the first day of the week for every brief tool at once,
+// so "this week" covers the same days in calendar-brief and mail-brief.
+const weekStartEnv = "BRIEF_WEEK_START"
+
+// defaultWeekStart is used when neither --week-start, the config file nor
+// BRIEF_WEEK_START says otherwise.
+const defaultWeekStart = time.Monday
+
+func parseWeekStart(s string) (time.Weekday, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "mon", "monday":
+		return time.Monday, nil
+	case "sun", "sunday":
+		return time.Sunday, nil
+	}
+	return 0, fmt.Errorf("unknown week start %q (want mon or sun)", s)
+}
+
+// resolveWeekStart picks the first of the flag, the config value and
+// BRIEF_WEEK_START that is set.
+func resolveWeekStart(flagValue, configValue string) (time.Weekday, error) {
+	for _, v := range []string{flagValue, configValue, os.Getenv(weekStartEnv)} {
+		if v != "" {
+			return parseWeekStart(v)
+		}
+	}
+	return defaultWeekStart, nil
+}
+
+// weekStartName is the spelling gog's --week-start takes.
+func weekStartName(start time.Weekday) string {
+	return strings.ToLower(start.String()[:3])
+}
+
+// startOfWeek returns midnight of the first day of the week containing day.
+func startOfWeek(day time.Time, start time.Weekday) time.Time {
+	midnight := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	return midnight.AddDate(0, 0, -((int(day.Weekday()) - int(start) + 7) % 7))
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Natural-language Dates ---
+
+var weekdayNames = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday, "일요일": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday, "월요일": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday, "tues": time.Tuesday, "화요일": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday, "수요일": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday, "thurs": time.Thursday, "목요일": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday, "금요일": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday, "토요일": time.Saturday,
+}
+
+var (
+	inDaysPattern        = regexp.MustCompile(`^in (\d+) (day|days|week|weeks)$`)
+	koreanInDaysPattern  = regexp.MustCompile(`^(\d+) ?(일|주) ?(후|뒤)$`)
+	weekdayPrefixPattern = regexp.MustCompile(`^(next|this|last|다음 ?주|이번 ?주|지난 ?주) (.+)$`)
+)
+
+// parseWhen resolves a phrase such as "tomorrow", "next monday", "in 3 days"
+// or "이번 주 금요일" to the first and last day it covers (equal for a single
+// day). A bare weekday means its next occurrence, today included; "next
+// <weekday>" means that day of next week, which begins on weekStart. Plain
+// dates are accepted too.
+func parseWhen(s string, now time.Time, weekStart time.Weekday) (time.Time, time.Time, error) {
+	phrase := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	first := startOfWeek(day, weekStart)
+	monday := startOfWeek(day, time.Monday) // weekends stay Saturday and Sunday
+	single := func(t time.Time) (time.Time, time.Time, error) { return t, t, nil }
+
+	switch phrase {
+	case "today", "tonight", "오늘":
+		return single(day)
+	case "tomorrow", "내일":
+		return single(day.AddDate(0, 0, 1))
+	case "day after tomorrow", "the day after tomorrow", "모레":
+		return single(day.AddDate(0, 0, 2))
+	case "yesterday", "어제":
+		return single(day.AddDate(0, 0, -1))
+	case "this week", "이번 주", "이번주":
+		return first, first.AddDate(0, 0, 6), nil
+	case "next week", "다음 주", "다음주":
+		return first.AddDate(0, 0, 7), first.AddDate(0, 0, 13), nil
+	case "last week", "지난 주", "지난주":
+		return first.AddDate(0, 0, -7), first.AddDate(0, 0, -1), nil
+	case "weekend", "this weekend", "주말", "이번 주말":
+		return monday.AddDate(0, 0, 5), monday.AddDate(0, 0, 6), nil
+	case "next weekend", "다음 주말":
+		return monday.AddDate(0, 0, 12), monday.AddDate(0, 0, 13), nil
+	}
+
+	if m := inDaysPattern.FindStringSubmatch(phrase); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if strings.HasPrefix(m[2], "week") {
+			n *= 7
+		}
+		return single(day.AddDate(0, 0, n))
+	}
+	if m := koreanInDaysPattern.FindStringSubmatch(phrase); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "주" {
+			n *= 7
+		}
+		return single(day.AddDate(0, 0, n))
+	}
+
+	if wd, ok := weekdayNames[phrase]; ok {
+		return single(day.AddDate(0, 0, (int(wd)-int(day.Weekday())+7)%7))
+	}
+	if m := weekdayPrefixPattern.FindStringSubmatch(phrase); m != nil {
+		if wd, ok := weekdayNames[m[2]]; ok {
+			offset := (int(wd) - int(weekStart) + 7) % 7 // days after the week's first
+			switch {
+			case m[1] == "next" || strings.HasPrefix(m[1], "다음"):
+				offset += 7
+			case m[1] == "last" || strings.HasPrefix(m[1], "지난"):
+				offset -= 7
+			}
+			return single(first.AddDate(0, 0, offset))
+		}
+	}
+
+	if t, err := parseDate(phrase, now.Location()); err == nil {
+		return single(t)
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("unrecognized --when %q (try \"tomorrow\", \"next monday\", \"in 3 days\" or YYYY-MM-DD)", s)
+}
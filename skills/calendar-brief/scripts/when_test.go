@@ -0,0 +1,62 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseWhen(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Seoul")
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, loc) // a Saturday
+	cases := []struct {
+		phrase      string
+		weekStart   time.Weekday
+		first, last string
+	}{
+		{"today", time.Monday, "2026-10-17", "2026-10-17"},
+		{"Tomorrow", time.Monday, "2026-10-18", "2026-10-18"},
+		{"모레", time.Monday, "2026-10-19", "2026-10-19"},
+		{"yesterday", time.Monday, "2026-10-16", "2026-10-16"},
+		{"in 3 days", time.Monday, "2026-10-20", "2026-10-20"},
+		{"in 2 weeks", time.Monday, "2026-10-31", "2026-10-31"},
+		{"3일 후", time.Monday, "2026-10-20", "2026-10-20"},
+		{"saturday", time.Monday, "2026-10-17", "2026-10-17"},
+		{"monday", time.Monday, "2026-10-19", "2026-10-19"},
+		{"this week", time.Monday, "2026-10-12", "2026-10-18"},
+		{"this week", time.Sunday, "2026-10-11", "2026-10-17"},
+		{"next week", time.Monday, "2026-10-19", "2026-10-25"},
+		{"last week", time.Sunday, "2026-10-04", "2026-10-10"},
+		{"next monday", time.Monday, "2026-10-19", "2026-10-19"},
+		{"next monday", time.Sunday, "2026-10-19", "2026-10-19"},
+		{"this friday", time.Monday, "2026-10-16", "2026-10-16"},
+		{"last friday", time.Monday, "2026-10-09", "2026-10-09"},
+		{"다음 주 금요일", time.Monday, "2026-10-23", "2026-10-23"},
+		{"weekend", time.Monday, "2026-10-17", "2026-10-18"},
+		{"weekend", time.Sunday, "2026-10-17", "2026-10-18"},
+		{"next weekend", time.Monday, "2026-10-24", "2026-10-25"},
+		{"2026-11-02", time.Monday, "2026-11-02", "2026-11-02"},
+	}
+	for _, c := range cases {
+		first, last, err := parseWhen(c.phrase, now, c.weekStart)
+		if err != nil {
+			t.Errorf("%q: %v", c.phrase, err)
+			continue
+		}
+		got := first.Format("2006-01-02") + ".." + last.Format("2006-01-02")
+		if want := c.first + ".." + c.last; got != want {
+			t.Errorf("%q (week starts %s): got %s, want %s", c.phrase, c.weekStart, got, want)
+		}
+		if first.Location() != loc || first.Hour() != 0 {
+			t.Errorf("%q: first %v is not midnight in %s", c.phrase, first, loc)
+		}
+	}
+}
+
+func TestParseWhenRejects(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	for _, phrase := range []string{"", "someday", "next fortnight", "in three days"} {
+		if _, _, err := parseWhen(phrase, now, time.Monday); err == nil {
+			t.Errorf("%q: want an error", phrase)
+		}
+	}
+}
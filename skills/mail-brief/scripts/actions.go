@@ -0,0 +1,147 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Action Items ---
+
+type ActionItem struct {
+	Text        string `json:"text"`
+	Due         string `json:"due,omitempty"`
+	Subject     string `json:"subject"`
+	FromEmail   string `json:"from_email"`
+	AccountType string `json:"account_type"`
+}
+
+var (
+	askPattern = regexp.MustCompile(`(?i)\b(please|pls|kindly)\s+\w+|\b(can|could|would)\s+you\b|\baction\s+required\b|\bdeadline\b|\bdue\b|\basap\b|부탁|요청|회신\s*바랍니다|검토\s*바랍니다`)
+
+	isoDatePattern     = regexp.MustCompile(`\b(\d{4})-(\d{1,2})-(\d{1,2})\b`)
+	slashDatePattern   = regexp.MustCompile(`\b(\d{1,2})/(\d{1,2})\b`)
+	koreanDatePattern  = regexp.MustCompile(`(\d{1,2})월\s*(\d{1,2})일`)
+	monthDatePattern   = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\.?\s+(\d{1,2})\b`)
+	weekdayDuePattern  = regexp.MustCompile(`(?i)\b(?:by|before|until|on)\s+(monday|tuesday|wednesday|thursday|friday|saturday|sunday|mon|tue|wed|thu|fri|sat|sun)\b`)
+	businessDuePattern = regexp.MustCompile(`(?i)\b(?:within|in)\s+(\d+)\s+business\s+days?\b|(\d+)\s*영업일`)
+	relativeDuePattern = regexp.MustCompile(`(?i)\b(today|tomorrow|eod|end of (?:the )?day|end of (?:the )?week|eow)\b|오늘|내일|이번\s*주`)
+)
+
+var monthAbbrev = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+var weekdayAbbrev = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// extractActionItems returns the sentences of text that contain an explicit
+// ask, each with a due date resolved relative to ref when one is mentioned.
+func extractActionItems(text string, ref time.Time) []ActionItem {
+	var items []ActionItem
+	for _, sentence := range splitSentences(text) {
+		if !askPattern.MatchString(sentence) {
+			continue
+		}
+		item := ActionItem{Text: sentence}
+		if due, ok := parseDueDate(sentence, ref); ok {
+			item.Due = due.Format("2006-01-02")
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func splitSentences(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return r == '\n' || r == '.' || r == '?' || r == '!'
+	})
+	sentences := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			sentences = append(sentences, f)
+		}
+	}
+	return sentences
+}
+
+// parseDueDate finds the first date-like expression in s. Dates without a
+// year that fall before ref are assumed to mean next year.
+func parseDueDate(s string, ref time.Time) (time.Time, bool) {
+	day := time.Date(ref.Year(), ref.Month(), ref.Day(), 0, 0, 0, 0, ref.Location())
+	withYear := func(month time.Month, d int) (time.Time, bool) {
+		if month < time.January || month > time.December || d < 1 || d > 31 {
+			return time.Time{}, false
+		}
+		t := time.Date(day.Year(), month, d, 0, 0, 0, 0, day.Location())
+		if t.Before(day) {
+			t = t.AddDate(1, 0, 0)
+		}
+		return t, true
+	}
+
+	if m := isoDatePattern.FindStringSubmatch(s); m != nil {
+		y, _ := strconv.Atoi(m[1])
+		mo, _ := strconv.Atoi(m[2])
+		d, _ := strconv.Atoi(m[3])
+		return time.Date(y, time.Month(mo), d, 0, 0, 0, 0, day.Location()), true
+	}
+	if m := koreanDatePattern.FindStringSubmatch(s); m != nil {
+		mo, _ := strconv.Atoi(m[1])
+		d, _ := strconv.Atoi(m[2])
+		return withYear(time.Month(mo), d)
+	}
+	if m := monthDatePattern.FindStringSubmatch(s); m != nil {
+		d, _ := strconv.Atoi(m[2])
+		return withYear(monthAbbrev[strings.ToLower(m[1][:3])], d)
+	}
+	if m := slashDatePattern.FindStringSubmatch(s); m != nil {
+		mo, _ := strconv.Atoi(m[1])
+		d, _ := strconv.Atoi(m[2])
+		return withYear(time.Month(mo), d)
+	}
+	if m := weekdayDuePattern.FindStringSubmatch(s); m != nil {
+		target := weekdayAbbrev[strings.ToLower(m[1][:3])]
+		delta := (int(target) - int(day.Weekday()) + 7) % 7
+		return day.AddDate(0, 0, delta), true
+	}
+	if m := businessDuePattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1] + m[2])
+		return addBusinessDays(day, n), true
+	}
+	if m := relativeDuePattern.FindString(s); m != "" {
+		switch m = strings.ToLower(m); {
+		case m == "tomorrow" || m == "내일":
+			return day.AddDate(0, 0, 1), true
+		case strings.Contains(m, "week") || m == "eow" || strings.HasPrefix(m, "이번"):
+			return day.AddDate(0, 0, (int(time.Friday)-int(day.Weekday())+7)%7), true
+		default:
+			return day, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseMessageDate understands the date formats gog emits for Gmail messages.
+func parseMessageDate(s string) (time.Time, bool) {
+	layouts := []string{
+		"2006-01-02 15:04",
+		"2006-01-02 15:04:05",
+		time.RFC3339,
+		time.RFC1123Z,
+		time.RFC1123,
+		"Mon, 2 Jan 2006 15:04:05 -0700",
+		"2 Jan 2006 15:04:05 -0700",
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, strings.TrimSpace(s), time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// --- Action Items ---
+
+func TestExtractActionItems(t *testing.T) {
+	ref := time.Date(2026, 10, 17, 9, 30, 0, 0, time.UTC) // a Saturday
+	type item struct{ text, due string }
+	cases := []struct {
+		text string
+		want []item
+	}{
+		{"FYI the office is closed on Monday", nil},
+		{"Please review the deck by Friday.", []item{{"Please review the deck by Friday", "2026-10-23"}}},
+		{"Lunch was great. Could you send the slides tomorrow?", []item{{"Could you send the slides tomorrow", "2026-10-18"}}},
+		{"Deadline is 2026-11-02 for the audit", []item{{"Deadline is 2026-11-02 for the audit", "2026-11-02"}}},
+		{"Please sign by Oct 3", []item{{"Please sign by Oct 3", "2027-10-03"}}},
+		{"Please send the numbers due 1/5", []item{{"Please send the numbers due 1/5", "2027-01-05"}}},
+		{"Please reply within 3 business days", []item{{"Please reply within 3 business days", "2026-10-21"}}},
+		{"Action required: renew the certificate by EOD", []item{{"Action required: renew the certificate by EOD", "2026-10-17"}}},
+		{"Can you finish it by end of week", []item{{"Can you finish it by end of week", "2026-10-23"}}},
+		{"회의록 검토 부탁드립니다 10월 20일까지", []item{{"회의록 검토 부탁드립니다 10월 20일까지", "2026-10-20"}}},
+		{"Please take a look when you can", []item{{"Please take a look when you can", ""}}},
+		{"Re: Q4 plan\nPlease approve the budget by Wed. Thanks! Could you also loop in Dana", []item{
+			{"Please approve the budget by Wed", "2026-10-21"},
+			{"Could you also loop in Dana", ""},
+		}},
+	}
+	for _, c := range cases {
+		var got []item
+		for _, a := range extractActionItems(c.text, ref) {
+			got = append(got, item{a.Text, a.Due})
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("extractActionItems(%q) = %v, want %v", c.text, got, c.want)
+		}
+	}
+}
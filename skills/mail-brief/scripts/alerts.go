@@ -0,0 +1,82 @@
+package main
+
+import (
+	"path"
+	"strings"
+	"time"
+)
+
+// --- Keyword Alerts ---
+
+// AlertRule fires for new mail whose sender matches From, a glob over the
+// address or its domain ("*.court.go.kr", "*@bank.com"), and whose subject
+// contains Subject, case-insensitively. An empty field matches anything.
+type AlertRule struct {
+	Name    string `json:"name"`
+	From    string `json:"from,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+type Alert struct {
+	Rule        string `json:"rule"`
+	Subject     string `json:"subject"`
+	FromEmail   string `json:"from_email"`
+	Date        string `json:"date"`
+	AccountType string `json:"account_type"`
+}
+
+func (r AlertRule) matches(msg SimplifiedMessage) bool {
+	if r.From == "" && r.Subject == "" {
+		return false
+	}
+	if r.From != "" {
+		pattern := strings.ToLower(r.From)
+		addr := strings.ToLower(msg.FromEmail)
+		okAddr, _ := path.Match(pattern, addr)
+		okDomain, _ := path.Match(pattern, emailDomain(addr))
+		if !okAddr && !okDomain {
+			return false
+		}
+	}
+	return r.Subject == "" || strings.Contains(strings.ToLower(msg.Subject), strings.ToLower(r.Subject))
+}
+
+// alertSeenDays bounds how long message IDs are remembered.
+const alertSeenDays = 30
+
+// checkAlerts returns the rule matches among messages no earlier --alerts
+// run has seen, then remembers them. The first run only seeds the store,
+// otherwise the whole mailbox window would alert at once.
+func checkAlerts(messages []SimplifiedMessage, rules []AlertRule, now time.Time) []Alert {
+	seen := map[string]string{}
+	seeded := loadState("alerts-seen.json", &seen)
+
+	alerts := []Alert{}
+	today := now.Format("2006-01-02")
+	for _, msg := range messages {
+		if msg.id == "" {
+			continue
+		}
+		if _, ok := seen[msg.id]; ok {
+			continue
+		}
+		seen[msg.id] = today
+		if !seeded {
+			continue
+		}
+		for _, r := range rules {
+			if r.matches(msg) {
+				alerts = append(alerts, Alert{Rule: r.Name, Subject: msg.Subject, FromEmail: msg.FromEmail, Date: msg.Date, AccountType: msg.AccountType})
+			}
+		}
+	}
+
+	cutoff := now.AddDate(0, 0, -alertSeenDays).Format("2006-01-02")
+	for id, day := range seen {
+		if day < cutoff {
+			delete(seen, id)
+		}
+	}
+	saveState("alerts-seen.json", seen)
+	return alerts
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// --- Audit Log ---
+
+// Every mailbox mutation (sending mail, creating or assigning labels) is
+// appended to audit.jsonl in the state directory, one JSON entry per line,
+// whether it succeeded or not. The file is never rewritten.
+
+type AuditEntry struct {
+	ID      string            `json:"id"`
+	Time    string            `json:"time"`
+	Action  string            `json:"action"` // send, label_create, label_add or undo
+	Account string            `json:"account"`
+	Params  map[string]string `json:"params"`
+	Error   string            `json:"error,omitempty"`
+}
+
+type AuditOutput struct {
+	Entries []AuditEntry   `json:"entries"`
+	Errors  []AccountError `json:"errors,omitempty"`
+}
+
+// recordAction appends one entry for an action that has just run; err is
+// the action's outcome. Failing to write the log is reported on stderr but
+// does not fail the action, which has already happened.
+func recordAction(action, account string, params map[string]string, err error) {
+	now := time.Now()
+	entry := AuditEntry{
+		ID:      strconv.FormatInt(now.UnixNano(), 36),
+		Time:    now.Format(time.RFC3339),
+		Action:  action,
+		Account: account,
+		Params:  params,
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	if werr := appendAudit(entry); werr != nil {
+		fmt.Fprintf(os.Stderr, "mail-brief: audit log not written: %v\n", werr)
+	}
+}
+
+func appendAudit(entry AuditEntry) error {
+	path, err := statePath("audit.jsonl")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// readAudit returns the logged actions, oldest first. Unparseable lines are
+// skipped.
+func readAudit() ([]AuditEntry, error) {
+	entries := []AuditEntry{}
+	path, err := statePath("audit.jsonl")
+	if err != nil {
+		return entries, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return entries, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e AuditEntry
+		if json.Unmarshal(scanner.Bytes(), &e) == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+func runAuditList() AuditOutput {
+	entries, err := readAudit()
+	output := AuditOutput{Entries: entries}
+	if err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+	}
+	return output
+}
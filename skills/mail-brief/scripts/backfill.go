@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// --- Backfill ---
+
+// --backfill --from DATE reads mail history from DATE through today into the
+// sender store (senders.json), so first-contact flags are right from the
+// first brief instead of after weeks of runs. Each account is searched a
+// week at a time with a pause between searches; a range that fills a whole
+// page is split until its searches fit, and a rate-limited search backs off
+// and is retried. Inbox sizes (inbox-history.json) are not backfilled: Gmail
+// only reports the current size, so past snapshots cannot be rebuilt.
+
+const (
+	backfillPageSize = 500
+	backfillPause    = time.Second
+	backfillRetries  = 4
+)
+
+var rateLimitPattern = regexp.MustCompile(`(?i)\b429\b|rate ?limit|quota|too many requests`)
+
+type BackfillOutput struct {
+	From       string            `json:"from"`
+	To         string            `json:"to"`
+	Accounts   []BackfillAccount `json:"accounts"`
+	Senders    int               `json:"senders"`     // in the store afterwards
+	NewSenders int               `json:"new_senders"` // added by this backfill
+	Errors     []AccountError    `json:"errors,omitempty"`
+}
+
+type BackfillAccount struct {
+	Email     string   `json:"email"`
+	Messages  int      `json:"messages"`
+	Searches  int      `json:"searches"`
+	Truncated []string `json:"truncated_days,omitempty"` // days with more mail than one search returns
+}
+
+// searchFunc runs one Gmail search for an account.
+type searchFunc func(query string) ([]GogMessage, error)
+
+// pacedSearch runs a search and then pauses, backing off and retrying while
+// gog reports a rate limit.
+func pacedSearch(search searchFunc, query string, pause time.Duration) ([]GogMessage, error) {
+	backoff := 4 * pause
+	for attempt := 0; ; attempt++ {
+		messages, err := search(query)
+		time.Sleep(pause)
+		if err == nil || attempt == backfillRetries || !rateLimitPattern.MatchString(err.Error()) {
+			return messages, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// backfillRange searches the whole days [from, to), splitting the range in
+// two while a search fills a page.
+func backfillRange(search searchFunc, from, to time.Time, pause time.Duration, stats *BackfillAccount, add func(GogMessage)) error {
+	query := fmt.Sprintf("after:%s before:%s", from.Format("2006/01/02"), to.Format("2006/01/02"))
+	messages, err := pacedSearch(search, query, pause)
+	stats.Searches++
+	if err != nil {
+		return fmt.Errorf("%s: %v", query, err)
+	}
+	if len(messages) >= backfillPageSize {
+		if days := int(to.Sub(from).Hours()/24 + 0.5); days > 1 {
+			mid := from.AddDate(0, 0, days/2)
+			if err := backfillRange(search, from, mid, pause, stats, add); err != nil {
+				return err
+			}
+			return backfillRange(search, mid, to, pause, stats, add)
+		}
+		stats.Truncated = append(stats.Truncated, from.Format("2006-01-02"))
+	}
+	stats.Messages += len(messages)
+	for _, m := range messages {
+		add(m)
+	}
+	return nil
+}
+
+// backfillAccount walks [from, to) a week at a time and returns the date
+// each sender was first seen on. It stops at the first failed search; what
+// was read until then is still returned.
+func backfillAccount(search searchFunc, from, to time.Time, pause time.Duration, stats *BackfillAccount) (map[string]string, error) {
+	seen := map[string]string{}
+	add := func(m GogMessage) {
+		_, addr := parseFrom(m.From)
+		if addr == "" {
+			return
+		}
+		date := ""
+		if t, ok := parseMessageDate(m.Date); ok {
+			date = t.Format("2006-01-02")
+		}
+		recordFirstSeen(seen, strings.ToLower(addr), date)
+	}
+	for start := from; start.Before(to); start = start.AddDate(0, 0, 7) {
+		end := start.AddDate(0, 0, 7)
+		if end.After(to) {
+			end = to
+		}
+		if err := backfillRange(search, start, end, pause, stats, add); err != nil {
+			return seen, err
+		}
+	}
+	return seen, nil
+}
+
+// recordFirstSeen keeps the earliest date a sender was seen on. An empty
+// date, as markFirstContacts seeds the store with, counts as earliest.
+func recordFirstSeen(seen map[string]string, addr, date string) {
+	if first, ok := seen[addr]; !ok || (first != "" && date < first) {
+		seen[addr] = date
+	}
+}
+
+// runBackfill backfills the sender store from every account, from the
+// start of from's day through now's.
+func runBackfill(accounts []Account, from, now time.Time, pause time.Duration) BackfillOutput {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, now.Location())
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	output := BackfillOutput{From: from.Format("2006-01-02"), To: now.Format("2006-01-02"), Accounts: []BackfillAccount{}}
+
+	stats := make([]BackfillAccount, len(accounts))
+	found := make([]map[string]string, len(accounts))
+	failed := make([]error, len(accounts))
+	parallel(len(accounts), func(i int) {
+		email := accounts[i].Email
+		stats[i].Email = email
+		search := func(query string) ([]GogMessage, error) {
+			return fetchMessages(context.Background(), email, query, backfillPageSize)
+		}
+		found[i], failed[i] = backfillAccount(search, from, to, pause, &stats[i])
+	})
+
+	store := map[string]string{}
+	loadState("senders.json", &store)
+	before := len(store)
+	for i, account := range accounts {
+		output.Accounts = append(output.Accounts, stats[i])
+		if failed[i] != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: failed[i].Error()})
+		}
+		for addr, date := range found[i] {
+			recordFirstSeen(store, addr, date)
+		}
+	}
+	output.Senders, output.NewSenders = len(store), len(store)-before
+	if err := saveState("senders.json", store); err != nil {
+		output.Errors = append(output.Errors, AccountError{Email: "senders", Error: err.Error()})
+	}
+	return output
+}
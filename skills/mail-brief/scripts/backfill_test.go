@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// --- Backfill ---
+
+func TestBackfillAccount(t *testing.T) {
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	// A full page for any search wider than a day, and always for 9/3;
+	// the first search is rate-limited once.
+	var queries []string
+	limited := false
+	search := func(query string) ([]GogMessage, error) {
+		if !limited {
+			limited = true
+			return nil, errors.New("Error 429: Too Many Requests")
+		}
+		queries = append(queries, query)
+		var after, before string
+		fmt.Sscanf(query, "after:%s before:%s", &after, &before)
+		start, _ := time.Parse("2006/01/02", after)
+		end, _ := time.Parse("2006/01/02", before)
+		n := 1
+		if end.Sub(start) > 24*time.Hour || start.Day() == 3 {
+			n = backfillPageSize
+		}
+		messages := make([]GogMessage, n)
+		for i := range messages {
+			messages[i] = GogMessage{From: fmt.Sprintf("Sender <s%d@example.com>", i%3), Date: start.Format("2006-01-02 15:04")}
+		}
+		return messages, nil
+	}
+
+	var stats BackfillAccount
+	seen, err := backfillAccount(search, from, to, 0, &stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(queries) == 0 || queries[0] != "after:2026/09/01 before:2026/09/08" {
+		t.Fatalf("first search after the retry = %v", queries)
+	}
+	// Every day ends up searched on its own, 9/3 still fills its page.
+	if want := []string{"2026-09-03"}; !reflect.DeepEqual(stats.Truncated, want) {
+		t.Errorf("truncated days = %v, want %v", stats.Truncated, want)
+	}
+	if stats.Searches != len(queries) {
+		t.Errorf("searches = %d, want %d", stats.Searches, len(queries))
+	}
+	if stats.Messages != 13+backfillPageSize {
+		t.Errorf("messages = %d, want %d", stats.Messages, 13+backfillPageSize)
+	}
+	want := map[string]string{"s0@example.com": "2026-09-01", "s1@example.com": "2026-09-03", "s2@example.com": "2026-09-03"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("first seen = %v, want %v", seen, want)
+	}
+}
+
+func TestRecordFirstSeen(t *testing.T) {
+	seen := map[string]string{"old@example.com": "", "later@example.com": "2026-10-01"}
+	recordFirstSeen(seen, "old@example.com", "2026-01-01")
+	recordFirstSeen(seen, "later@example.com", "2026-09-01")
+	recordFirstSeen(seen, "later@example.com", "2026-09-20")
+	recordFirstSeen(seen, "new@example.com", "2026-09-05")
+	want := map[string]string{"old@example.com": "", "later@example.com": "2026-09-01", "new@example.com": "2026-09-05"}
+	if !reflect.DeepEqual(seen, want) {
+		t.Errorf("seen = %v, want %v", seen, want)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// --- Benchmarks ---
+
+// The benchmarks run the parsing and per-message paths of a brief over a
+// synthetic 10k-message mailbox, e.g.
+//
+//	go test -run '^$' -bench . -benchmem
+const benchMessages = 10000
+
+var benchSenders = []string{
+	"Team Lead <lead@corp.example>",
+	"GitHub <noreply@github.com>",
+	"ＮＥＷＮＥＥＫ <news@newneek.co>",
+	"PayPal Support <service@paypa1.com>",
+	"Mail Delivery Subsystem <mailer-daemon@googlemail.com>",
+	"\"ceo@corp.example\" <ceo@corp-example.net>",
+}
+
+// syntheticMailbox returns gog's JSON for n messages from a few thousand
+// senders, mixing newsletters, bounces and lookalike domains.
+func syntheticMailbox(n int) []byte {
+	messages := make([]map[string]interface{}, n)
+	for i := range messages {
+		from := benchSenders[i%len(benchSenders)]
+		if i%3 == 0 {
+			from = fmt.Sprintf("Person %d <person%d@example%d.com>", i, i%2000, i%50)
+		}
+		labels := []interface{}{"INBOX"}
+		if i%2 == 0 {
+			labels = append(labels, "UNREAD")
+		}
+		messages[i] = map[string]interface{}{
+			"id":       fmt.Sprintf("m%d", i),
+			"threadId": fmt.Sprintf("t%d", i/3),
+			"date":     time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Minute).Format("2006-01-02 15:04"),
+			"from":     from,
+			"subject":  fmt.Sprintf("RE: Ｑ４ plan　review #%d — please reply by 2026-10-%02d", i, 1+i%28),
+			"snippet":  "Your message wasn't delivered to someone@example.com because the address couldn't be found",
+			"labels":   labels,
+		}
+	}
+	out, _ := json.Marshal(map[string]interface{}{"messages": messages})
+	return out
+}
+
+func benchRawMessages(b *testing.B) []GogMessage {
+	b.Helper()
+	list, err := decodeList[GogMessage](bytes.NewReader(syntheticMailbox(benchMessages)), "messages")
+	if err != nil {
+		b.Fatal(err)
+	}
+	return list.Items
+}
+
+func BenchmarkParseMessages(b *testing.B) {
+	data := syntheticMailbox(benchMessages)
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := decodeList[GogMessage](bytes.NewReader(data), "messages"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSimplifyMessages(b *testing.B) {
+	raw := benchRawMessages(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, m := range raw {
+			simplifyMessage(m, "work")
+		}
+	}
+}
+
+func BenchmarkNormalizeMessages(b *testing.B) {
+	raw := benchRawMessages(b)
+	msgs := make([]SimplifiedMessage, len(raw))
+	for i, m := range raw {
+		msgs[i] = simplifyMessage(m, "work")
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, msg := range msgs {
+			subjectKey(normalizeText(msg.Subject))
+			normalizeKey(normalizeText(msg.FromName))
+		}
+	}
+}
+
+func BenchmarkSuspiciousReasons(b *testing.B) {
+	raw := benchRawMessages(b)
+	msgs := make([]SimplifiedMessage, len(raw))
+	for i, m := range raw {
+		msgs[i] = simplifyMessage(m, "work")
+	}
+	myDomains := []string{"corp.example", "gmail.com"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j, m := range raw {
+			suspiciousReasons(m, msgs[j], myDomains)
+		}
+	}
+}
+
+// BenchmarkBrief runs the whole per-message path of a default brief with
+// --normalize, first-contact tracking included.
+func BenchmarkBrief(b *testing.B) {
+	home := b.TempDir()
+	b.Setenv("HOME", home)
+	b.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	data := syntheticMailbox(benchMessages)
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	opts := messageOptions{
+		Normalize: true,
+		MyDomains: []string{"corp.example", "gmail.com"},
+		Now:       now,
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		list, err := decodeList[GogMessage](bytes.NewReader(data), "messages")
+		if err != nil {
+			b.Fatal(err)
+		}
+		found := processMessages(list.Items, "work", opts)
+		markFirstContacts(found.Messages, now)
+	}
+}
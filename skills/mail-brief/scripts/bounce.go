@@ -0,0 +1,71 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// --- Delivery Failures ---
+
+type DeliveryFailure struct {
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+	Recipient   string `json:"recipient,omitempty"`
+	Reason      string `json:"reason,omitempty"`
+	AccountType string `json:"account_type"`
+}
+
+var (
+	bounceSenderPattern  = regexp.MustCompile(`(?i)^(mailer-daemon|postmaster|mail-daemon|mail delivery (subsystem|system))\b`)
+	bounceSubjectPattern = regexp.MustCompile(`(?i)delivery status notification \((failure|delay)\)|undeliverable|undelivered mail|mail delivery failed|returned mail|delivery failure|failure notice|메일 전송 실패|전송되지 않았습니다`)
+	emailPattern         = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// bounceReasons maps phrases found in bounce notices to a short reason. The
+// first match wins, so more specific phrases come first.
+var bounceReasons = []struct {
+	pattern *regexp.Regexp
+	reason  string
+}{
+	{regexp.MustCompile(`(?i)address not found|user unknown|no such user|does not exist|recipient address rejected|550[ -]5\.1\.1`), "address_not_found"},
+	{regexp.MustCompile(`(?i)mailbox (is )?full|over quota|quota exceeded|552[ -]5\.2\.2`), "mailbox_full"},
+	{regexp.MustCompile(`(?i)domain not found|dns error|host not found|no mx`), "domain_not_found"},
+	{regexp.MustCompile(`(?i)blocked|spam|policy|rejected|550[ -]5\.7`), "rejected_by_policy"},
+	{regexp.MustCompile(`(?i)message too large|size limit|552[ -]5\.3\.4`), "message_too_large"},
+	{regexp.MustCompile(`(?i)delay|temporar|will retry|deferred`), "delayed"},
+}
+
+func isBounce(msg SimplifiedMessage) bool {
+	local := strings.SplitN(msg.FromEmail, "@", 2)[0]
+	return bounceSenderPattern.MatchString(msg.FromName) ||
+		bounceSenderPattern.MatchString(local) ||
+		bounceSubjectPattern.MatchString(msg.Subject)
+}
+
+// detectDeliveryFailure inspects a message for bounce markers and pulls the
+// failed recipient and reason out of whatever text gog returned with it.
+func detectDeliveryFailure(raw GogMessage, msg SimplifiedMessage) (DeliveryFailure, bool) {
+	if !isBounce(msg) {
+		return DeliveryFailure{}, false
+	}
+
+	text := msg.Subject + "\n" + raw.Snippet + "\n" + raw.Body
+	failure := DeliveryFailure{
+		Date:        msg.Date,
+		Subject:     msg.Subject,
+		AccountType: msg.AccountType,
+	}
+	for _, addr := range emailPattern.FindAllString(text, -1) {
+		if !strings.EqualFold(addr, msg.FromEmail) {
+			failure.Recipient = addr
+			break
+		}
+	}
+	for _, r := range bounceReasons {
+		if r.pattern.MatchString(text) {
+			failure.Reason = r.reason
+			break
+		}
+	}
+	return failure, true
+}
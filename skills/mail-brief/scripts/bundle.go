@@ -0,0 +1,284 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// --- Config Bundle ---
+
+// --export-bundle packs the setup of both brief tools into one encrypted
+// file, so a new machine is restored with --import-bundle instead of by
+// hand: each skill's config.json (alert rules included), mail-brief's
+// accounts.json and the sender tags and notes. --include-state adds the
+// rest of the local state (pending actions, the audit log, snapshots),
+// except the fallback caches of fetched mail and events, which the next
+// good fetch rebuilds.
+//
+// The bundle is AES-256-GCM sealed under a key derived from
+// $BRIEF_BUNDLE_PASSPHRASE with PBKDF2-HMAC-SHA256. Either tool reads and
+// writes the same format. The two tools are separate modules with nothing
+// shared between them, so this file is copied into both rather than
+// imported; TestBundleCopiesMatch fails when the copies drift apart.
+
+const (
+	bundlePassphraseEnv = "BRIEF_BUNDLE_PASSPHRASE"
+	bundleMagic         = "BRIEFBUNDLE1"
+	bundleIterations    = 600000
+	bundleSaltSize      = 16
+)
+
+// bundleSkills are the tools whose files a bundle carries.
+var bundleSkills = []string{"calendar-brief", "mail-brief"}
+
+// bundleFile is one file in a bundle. Path is "skills/<skill>/<name>" for
+// files next to the skill, or "cache/<skill>/<name>" for its local state.
+type bundleFile struct {
+	Path string `json:"path"`
+	Data []byte `json:"data"`
+}
+
+type bundleContents struct {
+	Version int          `json:"version"`
+	Created string       `json:"created"`
+	Files   []bundleFile `json:"files"`
+}
+
+type BundleOutput struct {
+	Action  string   `json:"action"` // export or import
+	Path    string   `json:"path"`
+	Created string   `json:"created"` // when the bundle was exported
+	Files   []string `json:"files"`
+}
+
+// bundleRoots maps a bundle path's first element to its directory here.
+func bundleRoots() (map[string]string, error) {
+	skills := filepath.Dir(filepath.Dir(defaultConfigPath()))
+	cache, err := os.UserCacheDir()
+	if skills == "." || err != nil {
+		return nil, errors.New("cannot locate the home and cache directories")
+	}
+	return map[string]string{"skills": skills, "cache": cache}, nil
+}
+
+// localPath resolves a bundle path, refusing anything outside the brief
+// tools' own directories.
+func localPath(roots map[string]string, path string) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || roots[parts[0]] == "" || !containsString(bundleSkills, parts[1]) {
+		return "", fmt.Errorf("bundle entry %q is outside the brief tools", path)
+	}
+	for _, p := range parts[2:] {
+		if p == "" || p == "." || p == ".." {
+			return "", fmt.Errorf("bundle entry %q is outside the brief tools", path)
+		}
+	}
+	return filepath.Join(append([]string{roots[parts[0]]}, parts[1:]...)...), nil
+}
+
+// collectBundle reads the files to export. Missing files are skipped.
+func collectBundle(roots map[string]string, includeState bool) ([]bundleFile, error) {
+	var files []bundleFile
+	add := func(path string) error {
+		local, err := localPath(roots, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(local)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		files = append(files, bundleFile{Path: path, Data: data})
+		return nil
+	}
+	for _, skill := range bundleSkills {
+		for _, path := range []string{"skills/" + skill + "/config.json", "skills/" + skill + "/accounts.json", "cache/" + skill + "/annotations.json"} {
+			if err := add(path); err != nil {
+				return nil, err
+			}
+		}
+		if !includeState {
+			continue
+		}
+		dir := filepath.Join(roots["cache"], skill)
+		err := filepath.WalkDir(dir, func(local string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(dir, local)
+			switch {
+			case d.IsDir() && rel == "fallback":
+				return filepath.SkipDir
+			case d.IsDir() || rel == "annotations.json":
+				return nil
+			}
+			return add("cache/" + skill + "/" + filepath.ToSlash(rel))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// bundleKey derives the AES-256 key for passphrase and salt.
+func bundleKey(passphrase string, salt []byte, iterations int) []byte {
+	return pbkdf2.Key([]byte(passphrase), salt, iterations, 32, sha256.New)
+}
+
+// bundleCipher is AES-256-GCM under the key for passphrase and salt.
+func bundleCipher(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(bundleKey(passphrase, salt, iterations))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// sealBundle encrypts contents as magic, iterations, salt, nonce and the
+// sealed JSON.
+func sealBundle(contents bundleContents, passphrase string) ([]byte, error) {
+	plain, err := json.Marshal(contents)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, bundleSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := bundleCipher(passphrase, salt, bundleIterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	header := binary.BigEndian.AppendUint32([]byte(bundleMagic), bundleIterations)
+	header = append(append(header, salt...), nonce...)
+	return aead.Seal(header, nonce, plain, header), nil
+}
+
+// openBundle reverses sealBundle.
+func openBundle(data []byte, passphrase string) (bundleContents, error) {
+	var contents bundleContents
+	if !strings.HasPrefix(string(data), bundleMagic) || len(data) < len(bundleMagic)+4+bundleSaltSize {
+		return contents, errors.New("not a brief bundle")
+	}
+	rest := data[len(bundleMagic):]
+	iterations := int(binary.BigEndian.Uint32(rest))
+	if iterations < 1 || iterations > 10*bundleIterations {
+		return contents, errors.New("not a brief bundle")
+	}
+	salt := rest[4 : 4+bundleSaltSize]
+	aead, err := bundleCipher(passphrase, salt, iterations)
+	if err != nil {
+		return contents, err
+	}
+	headerLen := len(bundleMagic) + 4 + bundleSaltSize + aead.NonceSize()
+	if len(data) < headerLen {
+		return contents, errors.New("not a brief bundle")
+	}
+	plain, err := aead.Open(nil, data[headerLen-aead.NonceSize():headerLen], data[headerLen:], data[:headerLen])
+	if err != nil {
+		return contents, errors.New("wrong passphrase or damaged bundle")
+	}
+	if err := json.Unmarshal(plain, &contents); err != nil || contents.Version != 1 {
+		return contents, errors.New("unsupported bundle version")
+	}
+	return contents, nil
+}
+
+func bundlePassphrase() (string, error) {
+	passphrase := os.Getenv(bundlePassphraseEnv)
+	if passphrase == "" {
+		return "", fmt.Errorf("set %s to the bundle passphrase", bundlePassphraseEnv)
+	}
+	return passphrase, nil
+}
+
+// runExportBundle writes the bundle to path.
+func runExportBundle(path string, includeState bool, now time.Time) (BundleOutput, error) {
+	passphrase, err := bundlePassphrase()
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	roots, err := bundleRoots()
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	files, err := collectBundle(roots, includeState)
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	contents := bundleContents{Version: 1, Created: now.Format(time.RFC3339), Files: files}
+	sealed, err := sealBundle(contents, passphrase)
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	if err := os.WriteFile(path, sealed, 0o600); err != nil {
+		return BundleOutput{}, err
+	}
+	output := BundleOutput{Action: "export", Path: path, Created: contents.Created, Files: []string{}}
+	for _, f := range files {
+		output.Files = append(output.Files, f.Path)
+	}
+	return output, nil
+}
+
+// runImportBundle restores the files of the bundle at path, replacing any
+// that exist. Every entry is checked before anything is written.
+func runImportBundle(path string) (BundleOutput, error) {
+	passphrase, err := bundlePassphrase()
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	contents, err := openBundle(data, passphrase)
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	roots, err := bundleRoots()
+	if err != nil {
+		return BundleOutput{}, err
+	}
+	locals := make([]string, len(contents.Files))
+	for i, f := range contents.Files {
+		if locals[i], err = localPath(roots, f.Path); err != nil {
+			return BundleOutput{}, err
+		}
+	}
+	output := BundleOutput{Action: "import", Path: path, Created: contents.Created, Files: []string{}}
+	for i, f := range contents.Files {
+		if err := os.MkdirAll(filepath.Dir(locals[i]), 0o700); err != nil {
+			return output, err
+		}
+		if err := os.WriteFile(locals[i], f.Data, 0o600); err != nil {
+			return output, err
+		}
+		output.Files = append(output.Files, f.Path)
+	}
+	return output, nil
+}
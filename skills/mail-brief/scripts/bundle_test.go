@@ -0,0 +1,158 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// --- Config Bundle ---
+
+// The RFC 6070 vectors check the PBKDF2 the bundle relies on; they are
+// defined for HMAC-SHA1. The RFC 7914 ones check bundleKey itself.
+func TestPBKDF2KnownAnswers(t *testing.T) {
+	for _, c := range []struct {
+		password, salt string
+		iterations     int
+		want           string
+	}{
+		{"password", "salt", 1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 2, "ea6c014dc72d6f8ccd1ed92ace1d41f0d8de8957"},
+		{"password", "salt", 4096, "4b007901b765489abead49d926f721d065a429c1"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+		{"pass\x00word", "sa\x00lt", 4096, "56fa6aa75548099dcc37d7f03425e0c3"},
+	} {
+		if got := hex.EncodeToString(pbkdf2.Key([]byte(c.password), []byte(c.salt), c.iterations, len(c.want)/2, sha1.New)); got != c.want {
+			t.Errorf("RFC 6070 PBKDF2(%q, %q, %d) = %s, want %s", c.password, c.salt, c.iterations, got, c.want)
+		}
+	}
+
+	for _, c := range []struct {
+		password, salt string
+		iterations     int
+		want           string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56"},
+	} {
+		if got := hex.EncodeToString(bundleKey(c.password, []byte(c.salt), c.iterations)); got != c.want {
+			t.Errorf("bundleKey(%q, %q, %d) = %s, want %s", c.password, c.salt, c.iterations, got, c.want)
+		}
+	}
+}
+
+func TestBundleRoundTrip(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	t.Setenv(bundlePassphraseEnv, "correct horse")
+	roots, err := bundleRoots()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"skills/calendar-brief/config.json":    `{"alert_rules": [{"match": "board"}]}`,
+		"skills/mail-brief/accounts.json":      `[{"email": "me@imap.example"}]`,
+		"cache/mail-brief/annotations.json":    `{"cfo@corp.example": {"tags": ["vip"]}}`,
+		"cache/calendar-brief/pending.json":    `[]`,
+		"cache/mail-brief/fallback/ab.json":    `{"items": [{"body": "fetched mail"}]}`,
+		"cache/calendar-brief/last-brief.json": `{}`,
+	}
+	write := func() {
+		for path, data := range files {
+			local, err := localPath(roots, path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			os.MkdirAll(filepath.Dir(local), 0o700)
+			if err := os.WriteFile(local, []byte(data), 0o600); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write()
+	bundle := filepath.Join(t.TempDir(), "brief.bundle")
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	out, err := runExportBundle(bundle, false, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"skills/calendar-brief/config.json", "skills/mail-brief/accounts.json", "cache/mail-brief/annotations.json"}
+	if !reflect.DeepEqual(out.Files, want) {
+		t.Errorf("exported %v, want %v", out.Files, want)
+	}
+	out, err = runExportBundle(bundle, true, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"skills/calendar-brief/config.json", "cache/calendar-brief/last-brief.json", "cache/calendar-brief/pending.json", "skills/mail-brief/accounts.json", "cache/mail-brief/annotations.json"}
+	if !reflect.DeepEqual(out.Files, want) {
+		t.Errorf("exported with state %v, want %v (no fallback caches)", out.Files, want)
+	}
+
+	// A new machine: nothing there yet.
+	os.RemoveAll(roots["skills"])
+	os.RemoveAll(roots["cache"])
+	t.Setenv(bundlePassphraseEnv, "wrong")
+	if _, err := runImportBundle(bundle); err == nil {
+		t.Fatal("import with the wrong passphrase succeeded")
+	}
+	t.Setenv(bundlePassphraseEnv, "correct horse")
+	out, err = runImportBundle(bundle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Created != now.Format(time.RFC3339) || len(out.Files) != len(want) {
+		t.Errorf("import = %+v", out)
+	}
+	for _, path := range want {
+		local, _ := localPath(roots, path)
+		if data, err := os.ReadFile(local); err != nil || string(data) != files[path] {
+			t.Errorf("%s restored as %q, %v", path, data, err)
+		}
+	}
+}
+
+// Both tools read and write bundles, so their copies of the code must agree.
+func TestBundleCopiesMatch(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	self := filepath.Base(filepath.Dir(wd)) // skills/<tool>/scripts
+	for _, skill := range bundleSkills {
+		if skill == self {
+			continue
+		}
+		for _, name := range []string{"bundle.go", "bundle_test.go"} {
+			here, err := os.ReadFile(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			other := filepath.Join("..", "..", skill, "scripts", name)
+			there, err := os.ReadFile(other)
+			if err != nil {
+				t.Skipf("no %s to compare: %v", other, err)
+			}
+			if string(here) != string(there) {
+				t.Errorf("%s differs from %s", name, other)
+			}
+		}
+	}
+}
+
+func TestBundleRejectsForeignPaths(t *testing.T) {
+	roots := map[string]string{"skills": "/home/me/.claude/skills", "cache": "/home/me/.cache"}
+	for _, path := range []string{"skills/calendar-brief/../../../.ssh/id_rsa", "cache/other-tool/state.json", "bin/calendar-brief", "skills/mail-brief", "cache/mail-brief//x"} {
+		if local, err := localPath(roots, path); err == nil {
+			t.Errorf("localPath(%q) = %q, want an error", path, local)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package main
+
+import "time"
+
+// --- Business Days ---
+
+func isBusinessDay(d time.Time) bool {
+	return d.Weekday() != time.Saturday && d.Weekday() != time.Sunday
+}
+
+// addBusinessDays moves n weekdays forward from d; d itself never counts.
+func addBusinessDays(d time.Time, n int) time.Time {
+	for n > 0 {
+		d = d.AddDate(0, 0, 1)
+		if isBusinessDay(d) {
+			n--
+		}
+	}
+	return d
+}
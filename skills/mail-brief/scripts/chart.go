@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"strings"
+)
+
+// --- SVG Charts ---
+
+// barChartSVG draws a plain bar chart, one bar per label, sized to fit.
+// It needs no fonts or assets beyond what any browser or markdown viewer has.
+func barChartSVG(title string, labels []string, values []float64, unit string) string {
+	const (
+		barWidth = 36
+		gap      = 12
+		height   = 160
+		top      = 40
+		bottom   = 36
+		left     = 16
+	)
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	width := left*2 + len(values)*(barWidth+gap)
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="11">`+"\n", width, top+height+bottom)
+	fmt.Fprintf(&b, `<text x="%d" y="20" font-size="14" font-weight="bold">%s</text>`+"\n", left, html.EscapeString(title))
+	for i, v := range values {
+		h := 0
+		if peak > 0 {
+			h = int(v / peak * height)
+		}
+		x := left + i*(barWidth+gap)
+		y := top + height - h
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="#4a7bd0"><title>%s: %g%s</title></rect>`+"\n", x, y, barWidth, h, html.EscapeString(labels[i]), v, unit)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%g</text>`+"\n", x+barWidth/2, y-4, v)
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle">%s</text>`+"\n", x+barWidth/2, top+height+16, html.EscapeString(labels[i]))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func writeChart(path, svg string) error {
+	return os.WriteFile(path, []byte(svg), 0o644)
+}
+
+// mailVolumeChart charts how many messages arrived in each hour of the day.
+func mailVolumeChart(messages []SimplifiedMessage) string {
+	labels := make([]string, 24)
+	values := make([]float64, 24)
+	for h := range labels {
+		labels[h] = fmt.Sprintf("%02d", h)
+	}
+	for _, m := range messages {
+		if t, ok := parseMessageDate(m.Date); ok {
+			values[t.Hour()]++
+		}
+	}
+	return barChartSVG("Messages per hour", labels, values, "")
+}
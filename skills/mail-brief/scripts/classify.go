@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// --- External Classifier ---
+
+// classify_command lets an organization replace the built-in domain rules
+// (e.g. subsidiary domains count as work) without patching the code. It
+// runs through `sh -c` and receives {"kind": "account" or "message",
+// "items": [...]} on stdin, each item carrying the built-in type; it prints
+// a JSON array with one type per item, where "" keeps the built-in one.
+// Accounts are classified once at startup, messages once per run in a
+// single batch.
+
+type classifyRequest struct {
+	Kind  string      `json:"kind"`
+	Items interface{} `json:"items"`
+}
+
+type accountItem struct {
+	Email string `json:"email"`
+	Type  string `json:"type"`
+}
+
+func runClassifier(command, kind string, items interface{}, n int) ([]string, error) {
+	input, err := json.Marshal(classifyRequest{Kind: kind, Items: items})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = strings.NewReader(string(input))
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return nil, fmt.Errorf("classify_command failed: %s", errMsg)
+	}
+	var types []string
+	if err := json.Unmarshal(out, &types); err != nil {
+		return nil, fmt.Errorf("classify_command: want a JSON array of types: %v", err)
+	}
+	if len(types) != n {
+		return nil, fmt.Errorf("classify_command: got %d types for %d %ss", len(types), n, kind)
+	}
+	return types, nil
+}
+
+// classifyAccounts types discovered accounts with the built-in rules, then
+// lets command override them.
+func classifyAccounts(emails []string, command string) ([]Account, error) {
+	items := make([]accountItem, len(emails))
+	for i, email := range emails {
+		items[i] = accountItem{Email: email, Type: classifyAccount(email)}
+	}
+	if command != "" && len(items) > 0 {
+		types, err := runClassifier(command, "account", items, len(items))
+		if err != nil {
+			return nil, err
+		}
+		for i, t := range types {
+			if t != "" {
+				items[i].Type = t
+			}
+		}
+	}
+	accounts := make([]Account, len(items))
+	for i, it := range items {
+		accounts[i] = Account{Email: it.Email, Type: it.Type}
+	}
+	return accounts, nil
+}
+
+type messageItem struct {
+	Account   string `json:"account"`
+	FromEmail string `json:"from_email"`
+	FromName  string `json:"from_name"`
+	Subject   string `json:"subject"`
+	Type      string `json:"type"`
+}
+
+// classifyMessages lets command override each message's account_type.
+// emails[i] is the account messages[i] was fetched from. On failure the
+// built-in types are kept.
+func classifyMessages(messages []SimplifiedMessage, emails []string, command string) error {
+	if command == "" || len(messages) == 0 {
+		return nil
+	}
+	items := make([]messageItem, len(messages))
+	for i, m := range messages {
+		items[i] = messageItem{Account: emails[i], FromEmail: m.FromEmail, FromName: m.FromName, Subject: m.Subject, Type: m.AccountType}
+	}
+	types, err := runClassifier(command, "message", items, len(items))
+	if err != nil {
+		return err
+	}
+	for i, t := range types {
+		if t != "" {
+			messages[i].AccountType = t
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// --- Confidential Messages ---
+
+// Gmail confidential-mode mail and S/MIME or PGP encrypted mail is marked
+// confidential; its content is kept out of output (snippets, summaries)
+// unless --include-confidential is given.
+
+var (
+	confidentialModePattern = regexp.MustCompile(`(?i)gmail'?s? confidential mode|sent (you )?a confidential (email|message)|기밀 모드`)
+	pgpPattern              = regexp.MustCompile(`-----BEGIN PGP MESSAGE-----`)
+)
+
+var confidentialMimeTypes = map[string]bool{
+	"application/pkcs7-mime":    true,
+	"application/x-pkcs7-mime":  true,
+	"multipart/encrypted":       true,
+	"application/pgp-encrypted": true,
+}
+
+// hasConfidentialPart walks a Gmail payload looking for encrypted MIME parts.
+func hasConfidentialPart(part GogMessagePart) bool {
+	mimeType, _, _ := strings.Cut(strings.ToLower(part.MimeType), ";")
+	if confidentialMimeTypes[strings.TrimSpace(mimeType)] {
+		return true
+	}
+	for _, p := range part.Parts {
+		if hasConfidentialPart(p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isConfidential(raw GogMessage) bool {
+	if raw.Withheld {
+		return true
+	}
+	if hasConfidentialPart(GogMessagePart{MimeType: raw.MimeType, Parts: raw.Parts}) {
+		return true
+	}
+	if raw.Payload != nil && hasConfidentialPart(*raw.Payload) {
+		return true
+	}
+	for _, text := range []string{raw.Snippet, raw.Body} {
+		if confidentialModePattern.MatchString(text) || pgpPattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// --- Config ---
+
+// Config holds settings read from ~/.claude/skills/mail-brief/config.json.
+type Config struct {
+	// AlertRules are evaluated by --alerts.
+	AlertRules []AlertRule `json:"alert_rules"`
+
+	// ClassifyCommand, when set, decides the type of discovered accounts
+	// and of each message (see classifyAccounts and classifyMessages).
+	ClassifyCommand string `json:"classify_command"`
+
+	// WeekStart is "sun" (the default) or "mon"; see resolveWeekStart.
+	WeekStart string `json:"week_start"`
+}
+
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".claude", "skills", "mail-brief", "config.json")
+}
+
+// loadConfig reads the config file at path. A missing file is not an error;
+// it just yields the zero Config.
+func loadConfig(path string) (Config, error) {
+	var cfg Config
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	return cfg, nil
+}
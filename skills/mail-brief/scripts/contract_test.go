@@ -0,0 +1,469 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// --- Contract Tests ---
+
+// These tests run the real program against a fake gog serving testdata/gog
+// and hold its JSON output to testdata/output.schema.json, the contract the
+// skill prompt reads. The test binary plays both parts: linked as "gog" it
+// answers gog commands, and with runMainEnv set it runs main().
+
+const (
+	runMainEnv  = "MAIL_BRIEF_RUN_MAIN"
+	fixturesEnv = "FAKE_GOG_FIXTURES"
+	failEnv     = "FAKE_GOG_FAIL" // account whose gog calls fail
+
+	// fixtureNow is the day of the fixtures' mail, so ages and dates
+	// relative to now stay put.
+	fixtureNow = "2026-10-17T12:00:00+09:00"
+)
+
+func TestMain(m *testing.M) {
+	if filepath.Base(os.Args[0]) == "gog" {
+		os.Exit(fakeGog(os.Args[1:]))
+	}
+	if os.Getenv(runMainEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// fakeGog serves the gog commands mail-brief uses from fixture files.
+func fakeGog(args []string) int {
+	dir := os.Getenv(fixturesEnv)
+	account := ""
+	for _, a := range args {
+		if v, ok := strings.CutPrefix(a, "--account="); ok {
+			account = v
+		}
+	}
+	if account != "" && account == os.Getenv(failEnv) {
+		fmt.Fprintf(os.Stderr, "fake gog: %s is unavailable\n", account)
+		return 2
+	}
+
+	serve := func(name, fallback string) int {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			data = []byte(fallback)
+		} else if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		os.Stdout.Write(data)
+		return 0
+	}
+	switch {
+	case len(args) >= 2 && args[0] == "auth" && args[1] == "list":
+		return serve("auth.json", `{"accounts": []}`)
+	case len(args) >= 4 && args[0] == "gmail" && args[1] == "messages" && args[2] == "search":
+		if args[3] == "in:scheduled" {
+			return serve("scheduled-"+account+".json", `{"messages": []}`)
+		}
+		return serve("messages-"+account+".json", `{"messages": []}`)
+	case len(args) >= 3 && args[0] == "gmail" && args[1] == "get":
+		return serve("message-"+args[2]+".json", fmt.Sprintf(`{"id": %q}`, args[2]))
+	}
+	fmt.Fprintf(os.Stderr, "fake gog: unknown command %q\n", strings.Join(args, " "))
+	return 1
+}
+
+// runBrief runs mail-brief with args in a fresh home directory, so no
+// state carries over between runs, and returns its stdout and exit code.
+func runBrief(t *testing.T, env []string, args ...string) ([]byte, int) {
+	t.Helper()
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fixtures, err := filepath.Abs(filepath.Join("testdata", "gog"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bin := t.TempDir()
+	if err := os.Symlink(exe, filepath.Join(bin, "gog")); err != nil {
+		t.Skipf("cannot link fake gog: %v", err)
+	}
+	home := t.TempDir()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(),
+		runMainEnv+"=1",
+		fixturesEnv+"="+fixtures,
+		"PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"),
+		"HOME="+home,
+		"XDG_CACHE_HOME="+filepath.Join(home, ".cache"),
+		"BRIEF_READ_ONLY=",
+		nowEnv+"="+fixtureNow,
+		"TZ=Asia/Seoul",
+	)
+	cmd.Env = append(cmd.Env, env...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err = cmd.Run()
+	code := 0
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code = exitErr.ExitCode()
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	if stderr.Len() > 0 {
+		t.Logf("stderr: %s", stderr.String())
+	}
+	return stdout.Bytes(), code
+}
+
+// --- JSON Schema Subset ---
+
+// schema understands the parts of JSON Schema the contract uses: type,
+// required, properties, items, enum and local $refs to definitions.
+type schema struct {
+	Ref         string             `json:"$ref"`
+	Type        string             `json:"type"`
+	Required    []string           `json:"required"`
+	Properties  map[string]*schema `json:"properties"`
+	Items       *schema            `json:"items"`
+	Enum        []interface{}      `json:"enum"`
+	Definitions map[string]*schema `json:"definitions"`
+}
+
+func loadSchema(t *testing.T) *schema {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "output.schema.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatalf("output.schema.json: %v", err)
+	}
+	return &s
+}
+
+// validate returns one message per violation of s by v at path.
+func (s *schema) validate(root *schema, v interface{}, path string) []string {
+	if s.Ref != "" {
+		def, ok := root.Definitions[strings.TrimPrefix(s.Ref, "#/definitions/")]
+		if !ok {
+			return []string{fmt.Sprintf("%s: unknown $ref %s", path, s.Ref)}
+		}
+		return def.validate(root, v, path)
+	}
+	var problems []string
+	if s.Type != "" && !hasType(v, s.Type) {
+		return []string{fmt.Sprintf("%s: want %s, got %T", path, s.Type, v)}
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if e == v {
+				found = true
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("%s: %v is not one of %v", path, v, s.Enum))
+		}
+	}
+	if obj, ok := v.(map[string]interface{}); ok {
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: missing %q", path, name))
+			}
+		}
+		for name, prop := range s.Properties {
+			if field, ok := obj[name]; ok {
+				problems = append(problems, prop.validate(root, field, path+"."+name)...)
+			}
+		}
+	}
+	if arr, ok := v.([]interface{}); ok && s.Items != nil {
+		for i, item := range arr {
+			problems = append(problems, s.Items.validate(root, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return problems
+}
+
+func hasType(v interface{}, want string) bool {
+	switch want {
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		_, ok := v.(float64)
+		return ok
+	case "integer":
+		f, ok := v.(float64)
+		return ok && f == math.Trunc(f)
+	}
+	return false
+}
+
+// checkContract decodes out and fails the test on every schema violation.
+func checkContract(t *testing.T, s *schema, out []byte) map[string]interface{} {
+	t.Helper()
+	var v map[string]interface{}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatalf("output is not a JSON object: %v\n%s", err, out)
+	}
+	for _, p := range s.validate(s, v, "$") {
+		t.Error(p)
+	}
+	return v
+}
+
+// --- Flag Combinations ---
+
+var dateModes = [][]string{
+	{"--today"},
+	{"--yesterday"},
+	{"--this-week"},
+	{"--last-week"},
+	{"--last-week", "--week-start=mon"},
+	{"--date=2026-10-01"},
+}
+
+var accountSetups = []struct {
+	name         string
+	args         []string
+	env          []string
+	wantAccounts int
+	wantErrors   bool
+}{
+	{name: "discovered", wantAccounts: 2},
+	{name: "personal", args: []string{"--personal=me@gmail.com"}, wantAccounts: 1},
+	{name: "work", args: []string{"--work=me@corp.example"}, wantAccounts: 1},
+	{name: "both", args: []string{"--personal=me@gmail.com", "--work=me@corp.example"}, wantAccounts: 2},
+	{name: "shared", args: []string{"--shared=support@corp.example"}, wantAccounts: 3},
+	{name: "one-failing", env: []string{failEnv + "=me@corp.example"}, wantAccounts: 2, wantErrors: true},
+}
+
+func TestContractJSON(t *testing.T) {
+	s := loadSchema(t)
+	for _, setup := range accountSetups {
+		for _, mode := range dateModes {
+			setup, mode := setup, mode
+			t.Run(setup.name+"/"+strings.Join(mode, " "), func(t *testing.T) {
+				t.Parallel()
+				args := append(append([]string{"--retries=0"}, setup.args...), mode...)
+				out, code := runBrief(t, setup.env, args...)
+				if code != 0 {
+					t.Fatalf("exit %d: %s", code, out)
+				}
+				v := checkContract(t, s, out)
+				if accounts, _ := v["accounts"].([]interface{}); len(accounts) != setup.wantAccounts {
+					t.Errorf("got %d accounts, want %d", len(accounts), setup.wantAccounts)
+				}
+				if errs, _ := v["errors"].([]interface{}); (len(errs) > 0) != setup.wantErrors {
+					t.Errorf("errors = %v, want errors: %v", errs, setup.wantErrors)
+				}
+				if messages, _ := v["messages"].([]interface{}); len(messages) == 0 {
+					t.Error("no messages")
+				}
+			})
+		}
+	}
+}
+
+func TestContractJSONModifiers(t *testing.T) {
+	s := loadSchema(t)
+	for _, extra := range [][]string{
+		{"--normalize"},
+		{"--action-items"},
+		{"--scheduled"},
+		{"--action-items", "--normalize", "--scheduled"},
+		{"--person=lead@corp.example"},
+		{"--timings"},
+		{"--snippet"},
+		{"--snippet=40"},
+		{"--query=from:lead@corp.example"},
+		{"--label=Finance", "--label=CATEGORY_UPDATES"},
+		{"--exclude-label=none"},
+		{"--unread"},
+		{"--starred"},
+	} {
+		extra := extra
+		t.Run(strings.Join(extra, " "), func(t *testing.T) {
+			t.Parallel()
+			out, code := runBrief(t, nil, append([]string{"--this-week"}, extra...)...)
+			if code != 0 {
+				t.Fatalf("exit %d: %s", code, out)
+			}
+			checkContract(t, s, out)
+		})
+	}
+}
+
+// --fields trims each message to exactly the requested keys.
+func TestContractFields(t *testing.T) {
+	out, code := runBrief(t, nil, "--today", "--fields=subject,from_email")
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, out)
+	}
+	var v struct {
+		Messages []map[string]interface{} `json:"messages"`
+	}
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Messages) == 0 {
+		t.Fatal("no messages")
+	}
+	for i, m := range v.Messages {
+		_, hasSubject := m["subject"]
+		_, hasFrom := m["from_email"]
+		if len(m) != 2 || !hasSubject || !hasFrom {
+			t.Errorf("messages[%d] = %v, want only subject and from_email", i, m)
+		}
+	}
+}
+
+// --- Behavior ---
+
+type briefOutput struct {
+	Messages []struct {
+		Subject     string `json:"subject"`
+		AccountType string `json:"account_type"`
+		IsUnread    bool   `json:"is_unread"`
+		Summary     string `json:"summary"`
+		Snippet     string `json:"snippet"`
+	} `json:"messages"`
+	ActionItems []struct {
+		Text        string `json:"text"`
+		Due         string `json:"due"`
+		AccountType string `json:"account_type"`
+	} `json:"action_items"`
+	DeliveryFailures []struct {
+		Recipient string `json:"recipient"`
+		Reason    string `json:"reason"`
+	} `json:"delivery_failures"`
+	Errors []AccountError `json:"errors"`
+}
+
+func decodeBrief(t *testing.T, env []string, args ...string) briefOutput {
+	t.Helper()
+	out, code := runBrief(t, env, args...)
+	if code != 0 {
+		t.Fatalf("exit %d: %s", code, out)
+	}
+	var v briefOutput
+	if err := json.Unmarshal(out, &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// Each account's messages come through once, tagged with its type, and the
+// failed account is named in errors while the others still report.
+func TestContractAccounts(t *testing.T) {
+	v := decodeBrief(t, nil, "--today", "--shared=support@corp.example")
+	var got []string
+	for _, m := range v.Messages {
+		got = append(got, m.AccountType)
+	}
+	if want := "personal personal work work shared shared"; strings.Join(got, " ") != want {
+		t.Errorf("account types %v, want %s", got, want)
+	}
+
+	v = decodeBrief(t, []string{failEnv + "=me@corp.example"}, "--today", "--retries=0")
+	if len(v.Errors) != 1 || v.Errors[0].Email != "me@corp.example" {
+		t.Errorf("errors = %+v, want me@corp.example", v.Errors)
+	}
+	if len(v.Messages) != 2 {
+		t.Errorf("got %d messages, want the 2 from me@gmail.com", len(v.Messages))
+	}
+}
+
+// "by Friday" in a Saturday's mail is due the next Friday, the ask past the
+// first 200 characters of the body gog returns is found too, and the bounce
+// names the address that failed.
+func TestContractExtraction(t *testing.T) {
+	v := decodeBrief(t, nil, "--today", "--action-items")
+	if len(v.ActionItems) != 2 {
+		t.Fatalf("action items %+v, want two", v.ActionItems)
+	}
+	if item := v.ActionItems[0]; item.Text != "Please review the Q4 plan by Friday" || item.Due != "2026-10-23" || item.AccountType != "work" {
+		t.Errorf("action item %+v", item)
+	}
+	if item := v.ActionItems[1]; item.Text != "Could you also send the vendor contract to legal by Oct 22" || item.Due != "2026-10-22" || item.AccountType != "work" {
+		t.Errorf("action item from the body %+v", item)
+	}
+	if len(v.DeliveryFailures) != 1 || v.DeliveryFailures[0].Recipient != "jonh@example.com" || v.DeliveryFailures[0].Reason != "address_not_found" {
+		t.Errorf("delivery failures %+v", v.DeliveryFailures)
+	}
+}
+
+// --summarize-with gets the body gog returns for the message, which the
+// search results leave out.
+func TestContractSummarizeBody(t *testing.T) {
+	v := decodeBrief(t, nil, "--today", "--work=me@corp.example", "--summarize-with=grep -c 'release notes are attached'")
+	got := map[string]string{}
+	for _, m := range v.Messages {
+		got[m.Subject] = m.Summary
+	}
+	if s := got["[corp/app] Pull request merged"]; s != "1" {
+		t.Errorf("summarizer saw the body %s times, want once", s)
+	}
+}
+
+// --snippet=N previews the fetched body; bare --snippet keeps Gmail's.
+func TestContractSnippetBody(t *testing.T) {
+	for _, c := range []struct{ flag, want string }{
+		{"--snippet=20", "Merged #482 into mai…"},
+		{"--snippet", ""}, // the fixture has no Gmail snippet
+	} {
+		v := decodeBrief(t, nil, "--today", "--work=me@corp.example", c.flag)
+		for _, m := range v.Messages {
+			if m.Subject == "[corp/app] Pull request merged" && m.Snippet != c.want {
+				t.Errorf("%s: snippet = %q, want %q", c.flag, m.Snippet, c.want)
+			}
+		}
+	}
+}
+
+// Failures the prompt relies on seeing as a JSON error object.
+func TestContractErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{"--fields=nope"},
+		{"--read-only", "--undo=LAST"},
+		{"--this-week", "--week-start=tue"},
+		{"--backfill"},
+		{"--backfill", "--from=2099-01-01"},
+	} {
+		args := args
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			t.Parallel()
+			out, code := runBrief(t, nil, args...)
+			if code == 0 {
+				t.Fatalf("exit 0, want failure: %s", out)
+			}
+			var v struct {
+				Error string `json:"error"`
+			}
+			if err := json.Unmarshal(out, &v); err != nil || v.Error == "" {
+				t.Errorf("want {\"error\": ...}, got %s", out)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// --- Degradation Policy ---
+
+// An account that keeps failing should not slow down or clutter every brief.
+// After degradeAfter consecutive failures its budget shrinks to
+// degradedTimeout with no retries, and whenever it fails the last good
+// result for the same query is served instead, marked in meta.
+
+const (
+	degradeAfter    = 3
+	degradedTimeout = 10 * time.Second
+)
+
+type accountHealth struct {
+	Failures  int    `json:"failures"` // consecutive
+	LastError string `json:"last_error,omitempty"`
+	LastOK    string `json:"last_ok,omitempty"`
+}
+
+func loadHealth() map[string]accountHealth {
+	health := map[string]accountHealth{}
+	loadState("health.json", &health)
+	return health
+}
+
+// optionsFor shrinks opts for an account that has been failing.
+func optionsFor(opts jobOptions, h accountHealth) jobOptions {
+	if h.Failures >= degradeAfter {
+		opts.Timeout = min(opts.Timeout, degradedTimeout)
+		opts.Retries = 0
+	}
+	return opts
+}
+
+// recordHealth updates the account's failure streak after a fetch.
+func recordHealth(health map[string]accountHealth, email string, err error, now time.Time) {
+	h := health[email]
+	if err != nil {
+		h.Failures++
+		h.LastError = err.Error()
+	} else {
+		h = accountHealth{LastOK: now.Format(time.RFC3339)}
+	}
+	health[email] = h
+}
+
+// cachedResult is the last successful fetch of one query for one account.
+type cachedResult struct {
+	FetchedAt string       `json:"fetched_at"`
+	Items     []GogMessage `json:"items"`
+}
+
+// cacheName names the fallback file for an account and Gmail query.
+func cacheName(email, query string) string {
+	sum := sha1.Sum([]byte(email + "\x00" + query))
+	return fmt.Sprintf("fallback/%s.json", hex.EncodeToString(sum[:8]))
+}
+
+// saveFallback caches a successful fetch. Confidential messages are cached
+// without their snippet, body and MIME tree unless includeConfidential is
+// set, the same as in output.
+func saveFallback(email, query string, items []GogMessage, includeConfidential bool, now time.Time) {
+	if !includeConfidential {
+		kept := make([]GogMessage, len(items))
+		for i, m := range items {
+			if isConfidential(m) {
+				m.Snippet, m.Body, m.Parts, m.Payload = "", "", nil, nil
+				m.Withheld = true
+			}
+			kept[i] = m
+		}
+		items = kept
+	}
+	saveState(cacheName(email, query), cachedResult{FetchedAt: now.Format(time.RFC3339), Items: items})
+}
+
+func loadFallback(email, query string) (cachedResult, bool) {
+	var c cachedResult
+	ok := loadState(cacheName(email, query), &c)
+	return c, ok
+}
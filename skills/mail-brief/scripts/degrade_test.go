@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// --- Degradation Policy ---
+
+func TestFallbackWithholdsConfidential(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", dir)
+	t.Setenv("HOME", dir)
+
+	const secret = "the merger closes friday"
+	items := []GogMessage{
+		{ID: "plain", Subject: "Lunch", Snippet: "see you at noon", Body: "see you at noon"},
+		{ID: "pgp", Subject: "Deal", Snippet: secret, Body: "-----BEGIN PGP MESSAGE-----\n" + secret},
+		{ID: "smime", Subject: "Signed", Body: secret, Payload: &GogMessagePart{
+			MimeType: "application/pkcs7-mime", Body: GogPartBody{Data: secret},
+		}},
+	}
+	now := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+
+	saveFallback("me@corp.example", "newer_than:1d", items, false, now)
+	path, err := statePath(cacheName("me@corp.example", "newer_than:1d"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), secret) {
+		t.Fatalf("fallback cache %s holds confidential content:\n%s", filepath.Base(path), data)
+	}
+	if !strings.Contains(string(data), "see you at noon") {
+		t.Error("fallback cache dropped a message that is not confidential")
+	}
+
+	cached, ok := loadFallback("me@corp.example", "newer_than:1d")
+	if !ok || len(cached.Items) != 3 {
+		t.Fatalf("loadFallback = %d items, %v", len(cached.Items), ok)
+	}
+	for _, m := range cached.Items {
+		if want := m.ID != "plain"; isConfidential(m) != want {
+			t.Errorf("cached %s: confidential = %v, want %v", m.ID, !want, want)
+		}
+	}
+
+	saveFallback("me@corp.example", "newer_than:1d", items, true, now)
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), secret) {
+		t.Error("--include-confidential should cache confidential content")
+	}
+}
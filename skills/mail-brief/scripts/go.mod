@@ -1,3 +1,5 @@
 module mail-brief
 
 go 1.21
+
+require golang.org/x/crypto v0.32.0
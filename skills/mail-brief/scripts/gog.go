@@ -0,0 +1,59 @@
+package main
+
+// --- gog Response Models ---
+
+// These mirror the parts of gog's --json output that the brief reads.
+// Fields gog adds later are ignored by encoding/json. A list item with a
+// known field of an unexpected type is kept by decodeItems with that field
+// left empty, and counted in the account's meta as malformed, instead of
+// failing the whole list. omitempty keeps the fallback cache small.
+
+// GogMessage is a Gmail message as gog prints it. Search results use gog's
+// flattened fields (from, subject, date, ...); thread messages may instead
+// come back in the Gmail API shape, with headers and MIME parts under
+// payload.
+type GogMessage struct {
+	ID                    string           `json:"id,omitempty"`
+	ThreadID              string           `json:"threadId,omitempty"`
+	Date                  string           `json:"date,omitempty"`
+	From                  string           `json:"from,omitempty"`
+	To                    string           `json:"to,omitempty"`
+	Subject               string           `json:"subject,omitempty"`
+	Snippet               string           `json:"snippet,omitempty"`
+	Body                  string           `json:"body,omitempty"`
+	Labels                []string         `json:"labels,omitempty"`
+	Attachments           []string         `json:"attachments,omitempty"` // file names
+	AuthenticationResults string           `json:"authenticationResults,omitempty"`
+	InternalDate          string           `json:"internalDate,omitempty"` // epoch milliseconds
+	MimeType              string           `json:"mimeType,omitempty"`
+	Parts                 []GogMessagePart `json:"parts,omitempty"`
+	Payload               *GogMessagePart  `json:"payload,omitempty"`
+
+	// Withheld is never printed by gog: it marks a cached copy of a
+	// confidential message whose content was dropped, see saveFallback.
+	Withheld bool `json:"withheld,omitempty"`
+}
+
+// GogMessagePart is one node of a Gmail API MIME tree.
+type GogMessagePart struct {
+	MimeType string           `json:"mimeType,omitempty"`
+	Filename string           `json:"filename,omitempty"`
+	Headers  []GogHeader      `json:"headers,omitempty"`
+	Body     GogPartBody      `json:"body"`
+	Parts    []GogMessagePart `json:"parts,omitempty"`
+}
+
+type GogHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type GogPartBody struct {
+	Data string `json:"data,omitempty"` // base64url
+}
+
+// GogThread is a Gmail thread as gog prints it.
+type GogThread struct {
+	ID       string       `json:"id,omitempty"`
+	Messages []GogMessage `json:"messages"`
+}
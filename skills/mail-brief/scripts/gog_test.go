@@ -0,0 +1,44 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// --- gog Response Models ---
+
+func TestParseMessagesTolerant(t *testing.T) {
+	const list = `[
+	  {"id": "a", "threadId": "t1", "from": "Bob <bob@corp.example>", "subject": "Plan",
+	   "labels": ["INBOX", "UNREAD"], "sizeEstimate": 1234, "historyId": "99"},
+	  {"id": "b", "labels": "INBOX"},
+	  null,
+	  {"id": "c", "payload": {"mimeType": "multipart/mixed", "parts": [{"mimeType": "application/pkcs7-mime", "body": {"size": 10}}]}}
+	]`
+	decoded, err := decodeList[GogMessage](strings.NewReader(list), "messages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	messages := decoded.Items
+	var ids []string
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+	}
+	if got := strings.Join(ids, ","); got != "a,b,c" {
+		t.Fatalf("decoded messages %s, want a,b,c (unknown fields ignored, mistyped fields left empty)", got)
+	}
+	if decoded.Malformed != 2 {
+		t.Errorf("Malformed = %d, want 2 (the mistyped labels and the null)", decoded.Malformed)
+	}
+	if messages[1].Labels != nil {
+		t.Errorf("mistyped labels decoded as %q", messages[1].Labels)
+	}
+
+	plan := simplifyMessage(messages[0], "work")
+	if plan.FromEmail != "bob@corp.example" || !plan.IsUnread || strings.Join(plan.Labels, ",") != "INBOX" {
+		t.Errorf("simplified message = %+v", plan)
+	}
+	if !isConfidential(messages[2]) {
+		t.Error("S/MIME part in payload not detected as confidential")
+	}
+}
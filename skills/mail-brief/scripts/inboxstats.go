@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// --- Inbox Hygiene Stats ---
+
+// unreadSampleSize caps how many unread inbox messages are pulled to compute
+// the oldest-unread date and the per-label breakdown.
+const unreadSampleSize = 500
+
+type InboxStats struct {
+	Email           string         `json:"email"`
+	Type            string         `json:"type"`
+	InboxTotal      int            `json:"inbox_total"`
+	InboxUnread     int            `json:"inbox_unread"`
+	OldestUnread    string         `json:"oldest_unread,omitempty"`
+	UnreadByLabel   map[string]int `json:"unread_by_label"`
+	SampleTruncated bool           `json:"sample_truncated"`
+	WeekDelta       *int           `json:"week_over_week_delta,omitempty"`
+}
+
+type InboxStatsOutput struct {
+	Inbox  []InboxStats   `json:"inbox"`
+	Errors []AccountError `json:"errors,omitempty"`
+}
+
+// inboxSnapshot is one recorded inbox size, kept so later runs can report a
+// week-over-week delta.
+type inboxSnapshot struct {
+	Date   string `json:"date"`
+	Total  int    `json:"total"`
+	Unread int    `json:"unread"`
+}
+
+// recordSnapshot stores today's snapshot (replacing an earlier one from the
+// same day), drops entries older than 60 days, and returns the inbox total
+// from the snapshot closest to a week ago, if there is one at least 7 days old.
+func recordSnapshot(snapshots []inboxSnapshot, today inboxSnapshot, now time.Time) ([]inboxSnapshot, *int) {
+	var weekAgo *int
+	bestGap := time.Duration(-1)
+	kept := make([]inboxSnapshot, 0, len(snapshots)+1)
+
+	for _, s := range snapshots {
+		d, err := time.ParseInLocation("2006-01-02", s.Date, now.Location())
+		if err != nil || s.Date == today.Date || now.Sub(d) > 60*24*time.Hour {
+			continue
+		}
+		kept = append(kept, s)
+		if age := now.Sub(d); age >= 7*24*time.Hour {
+			gap := age - 7*24*time.Hour
+			if bestGap < 0 || gap < bestGap {
+				total := s.Total
+				weekAgo, bestGap = &total, gap
+			}
+		}
+	}
+	kept = append(kept, today)
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Date < kept[j].Date })
+	return kept, weekAgo
+}
+
+func fetchLabelCounts(accountEmail, labelID string) (total, unread int, err error) {
+	out, err := runGog("gmail", "labels", "get", labelID, "--json", fmt.Sprintf("--account=%s", accountEmail))
+	if err != nil {
+		return 0, 0, err
+	}
+	var label struct {
+		MessagesTotal  int `json:"messagesTotal"`
+		MessagesUnread int `json:"messagesUnread"`
+	}
+	if err := json.Unmarshal(out, &label); err != nil {
+		return 0, 0, fmt.Errorf("unexpected JSON format from gog")
+	}
+	return label.MessagesTotal, label.MessagesUnread, nil
+}
+
+func collectInboxStats(account Account) (InboxStats, error) {
+	stats := InboxStats{Email: account.Email, Type: account.Type, UnreadByLabel: map[string]int{}}
+
+	total, unread, err := fetchLabelCounts(account.Email, "INBOX")
+	if err != nil {
+		return stats, err
+	}
+	stats.InboxTotal, stats.InboxUnread = total, unread
+
+	rawMessages, err := fetchMessages(context.Background(), account.Email, "in:inbox is:unread", unreadSampleSize)
+	if err != nil {
+		return stats, err
+	}
+	stats.SampleTruncated = len(rawMessages) >= unreadSampleSize
+
+	var oldest time.Time
+	for _, raw := range rawMessages {
+		msg := simplifyMessage(raw, account.Type)
+		for _, label := range msg.Labels {
+			if label != "INBOX" {
+				stats.UnreadByLabel[label]++
+			}
+		}
+		if t, ok := parseMessageDate(msg.Date); ok && (oldest.IsZero() || t.Before(oldest)) {
+			oldest = t
+		}
+	}
+	if !oldest.IsZero() {
+		stats.OldestUnread = oldest.Format(time.RFC3339)
+	}
+	return stats, nil
+}
+
+func runInboxStats(accounts []Account) InboxStatsOutput {
+	output := InboxStatsOutput{Inbox: []InboxStats{}}
+	now := time.Now()
+	history := map[string][]inboxSnapshot{}
+	loadState("inbox-history.json", &history)
+
+	collected := make([]InboxStats, len(accounts))
+	failed := make([]error, len(accounts))
+	parallel(len(accounts), func(i int) {
+		collected[i], failed[i] = collectInboxStats(accounts[i])
+	})
+	for i, account := range accounts {
+		stats, err := collected[i], failed[i]
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			continue
+		}
+		snapshot := inboxSnapshot{Date: now.Format("2006-01-02"), Total: stats.InboxTotal, Unread: stats.InboxUnread}
+		var weekAgo *int
+		history[account.Email], weekAgo = recordSnapshot(history[account.Email], snapshot, now)
+		if weekAgo != nil {
+			delta := stats.InboxTotal - *weekAgo
+			stats.WeekDelta = &delta
+		}
+		output.Inbox = append(output.Inbox, stats)
+	}
+
+	if err := saveState("inbox-history.json", history); err != nil {
+		output.Errors = append(output.Errors, AccountError{Email: "history", Error: err.Error()})
+	}
+	return output
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// --- ICS Invite Replies ---
+
+// Replies for organizers outside Google Calendar. Google-hosted invites are
+// better answered through the calendar RSVP, which keeps both sides in sync.
+
+type InviteReplyOutput struct {
+	ThreadID  string         `json:"thread_id"`
+	Account   *Account       `json:"account,omitempty"`
+	Summary   string         `json:"summary,omitempty"`
+	Organizer string         `json:"organizer,omitempty"`
+	UID       string         `json:"uid,omitempty"`
+	Response  string         `json:"response"`
+	ICS       string         `json:"ics,omitempty"`
+	Sent      bool           `json:"sent"`
+	Staged    *PendingAction `json:"staged,omitempty"` // with --require-approval
+	Errors    []AccountError `json:"errors,omitempty"`
+}
+
+var partStats = map[string]string{
+	"accept":    "ACCEPTED",
+	"decline":   "DECLINED",
+	"tentative": "TENTATIVE",
+}
+
+var replySubjectPrefix = map[string]string{
+	"accept":    "Accepted",
+	"decline":   "Declined",
+	"tentative": "Tentatively accepted",
+}
+
+// findCalendarPart returns the decoded body of the first text/calendar part.
+func findCalendarPart(part GogMessagePart) (string, bool) {
+	return findTextPart(part, "text/calendar")
+}
+
+// icsLines unfolds an iCalendar body (RFC 5545 3.1) into logical lines.
+func icsLines(ics string) []string {
+	var lines []string
+	for _, l := range strings.Split(strings.ReplaceAll(ics, "\r\n", "\n"), "\n") {
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// icsEvent keeps the raw property lines of the first VEVENT, keyed by name,
+// plus the calendar's METHOD.
+type icsEvent struct {
+	method string
+	props  map[string]string
+}
+
+func parseICS(ics string) icsEvent {
+	ev := icsEvent{props: map[string]string{}}
+	inEvent := false
+	for _, line := range icsLines(ics) {
+		name, value, _ := strings.Cut(line, ":")
+		key, _, _ := strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case key == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = len(ev.props) == 0
+		case key == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+		case key == "METHOD" && !inEvent:
+			ev.method = strings.ToUpper(value)
+		case inEvent:
+			if _, seen := ev.props[key]; !seen {
+				ev.props[key] = line
+			}
+		}
+	}
+	return ev
+}
+
+func (ev icsEvent) value(key string) string {
+	_, v, _ := strings.Cut(ev.props[key], ":")
+	return v
+}
+
+// buildICSReply answers a REQUEST for attendee with the given PARTSTAT.
+func buildICSReply(ev icsEvent, attendee, partStat string, now time.Time) string {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//claude-settings//mail-brief//EN",
+		"VERSION:2.0",
+		"METHOD:REPLY",
+		"BEGIN:VEVENT",
+	}
+	for _, key := range []string{"UID", "SEQUENCE", "RECURRENCE-ID", "DTSTART", "DTEND", "SUMMARY", "ORGANIZER"} {
+		if line, ok := ev.props[key]; ok {
+			lines = append(lines, line)
+		}
+	}
+	lines = append(lines,
+		"DTSTAMP:"+now.UTC().Format("20060102T150405Z"),
+		fmt.Sprintf("ATTENDEE;PARTSTAT=%s:mailto:%s", partStat, attendee),
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(foldICSLine(line))
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// foldICSLine splits a content line into 75-octet chunks without breaking a
+// UTF-8 sequence.
+func foldICSLine(line string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		n := utf8.RuneLen(r)
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
+
+func sendICSReply(account Account, to, subject, ics string) error {
+	dir, err := os.MkdirTemp("", "mail-brief-ics")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "invite.ics")
+	if err := os.WriteFile(path, []byte(ics), 0o600); err != nil {
+		return err
+	}
+	_, err = runGog("gmail", "send",
+		fmt.Sprintf("--to=%s", to),
+		fmt.Sprintf("--subject=%s", subject),
+		fmt.Sprintf("--body=%s", subject),
+		fmt.Sprintf("--attach=%s", path),
+		fmt.Sprintf("--account=%s", account.Email))
+	recordAction("send", account.Email, map[string]string{"to": to, "subject": subject, "attachment": "invite.ics"}, err)
+	return err
+}
+
+// runInviteReply finds the newest invite in a thread and builds the ICS
+// REPLY for it. Nothing is sent unless send is set, and with stage set the
+// send is queued for --approve instead.
+func runInviteReply(accounts []Account, threadID, response string, send, stage bool) InviteReplyOutput {
+	output := InviteReplyOutput{ThreadID: threadID, Response: response}
+	fail := func(email string, err error) InviteReplyOutput {
+		output.Errors = append(output.Errors, AccountError{Email: email, Error: err.Error()})
+		return output
+	}
+	partStat, ok := partStats[response]
+	if !ok {
+		return fail("", fmt.Errorf("unknown --rsvp %q (want accept, decline or tentative)", response))
+	}
+
+	var thread *GogThread
+	for i, account := range accounts {
+		t, err := fetchThread(account.Email, threadID)
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			continue
+		}
+		thread, output.Account, output.Errors = t, &accounts[i], nil
+		break
+	}
+	if thread == nil {
+		return output
+	}
+	account := *output.Account
+
+	var ics string
+	for i := len(thread.Messages) - 1; i >= 0 && ics == ""; i-- {
+		if payload := thread.Messages[i].Payload; payload != nil {
+			ics, _ = findCalendarPart(*payload)
+		}
+	}
+	if ics == "" {
+		return fail(account.Email, fmt.Errorf("no inline text/calendar invite in thread %s", threadID))
+	}
+
+	ev := parseICS(ics)
+	output.UID = ev.value("UID")
+	output.Summary = ev.value("SUMMARY")
+	output.Organizer = strings.TrimPrefix(strings.ToLower(ev.value("ORGANIZER")), "mailto:")
+	switch {
+	case ev.method != "" && ev.method != "REQUEST":
+		return fail(account.Email, fmt.Errorf("invite method is %s, not REQUEST", ev.method))
+	case output.UID == "" || output.Organizer == "":
+		return fail(account.Email, fmt.Errorf("invite has no UID or ORGANIZER"))
+	case strings.HasSuffix(strings.ToLower(output.UID), "@google.com"):
+		return fail(account.Email, fmt.Errorf("invite comes from Google Calendar; RSVP through the calendar instead"))
+	}
+
+	output.ICS = buildICSReply(ev, account.Email, partStat, time.Now())
+	if send {
+		subject := fmt.Sprintf("%s: %s", replySubjectPrefix[response], output.Summary)
+		if stage {
+			p, err := stageAction("send", account.Email, map[string]string{"to": output.Organizer, "subject": subject, "ics": output.ICS})
+			if err != nil {
+				return fail(account.Email, err)
+			}
+			output.Staged = &p
+			return output
+		}
+		if err := sendICSReply(account, output.Organizer, subject, output.ICS); err != nil {
+			return fail(account.Email, err)
+		}
+		output.Sent = true
+	}
+	return output
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// --- Account Jobs ---
+
+// Meta reports how each account's fetch went, so a slow or flaky account is
+// visible even when the run as a whole succeeds.
+type Meta struct {
+	Accounts []AccountMeta `json:"accounts"`
+}
+
+type AccountMeta struct {
+	Email      string `json:"email"`
+	Status     string `json:"status"` // ok, error, timeout or cached
+	Attempts   int    `json:"attempts"`
+	DurationMs int64  `json:"duration_ms"`
+
+	// Degraded is set once the account has failed degradeAfter times in a
+	// row; CachedAt dates the fallback data served when it failed again.
+	Degraded bool   `json:"degraded,omitempty"`
+	CachedAt string `json:"cached_at,omitempty"`
+
+	// Malformed counts the items gog printed that did not fit the expected
+	// shape (see gogList); mistyped fields of those kept are left empty.
+	Malformed int `json:"malformed,omitempty"`
+}
+
+type jobOptions struct {
+	Timeout time.Duration // budget for all attempts of one account
+	Retries int           // extra attempts after a failure
+}
+
+// runAccountJob runs fn under the account's own deadline, retrying failures
+// until the retries or the deadline run out. fn must be safe to rerun.
+func runAccountJob(email string, opts jobOptions, fn func(ctx context.Context) error) (AccountMeta, error) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
+	defer cancel()
+
+	meta := AccountMeta{Email: email, Status: "ok"}
+	var err error
+	for {
+		meta.Attempts++
+		if err = fn(ctx); err == nil || ctx.Err() != nil || meta.Attempts > opts.Retries {
+			break
+		}
+	}
+	meta.DurationMs = time.Since(start).Milliseconds()
+
+	if err != nil {
+		meta.Status = "error"
+		if ctx.Err() == context.DeadlineExceeded {
+			meta.Status = "timeout"
+			err = fmt.Errorf("timed out after %s", opts.Timeout)
+		}
+	}
+	return meta, err
+}
+
+// Timings shows where a run's time went, with --timings. Accounts are
+// fetched in parallel, so FetchMs is roughly the slowest account rather
+// than their sum.
+type Timings struct {
+	TotalMs  int64           `json:"total_ms"`
+	FetchMs  int64           `json:"fetch_ms"`
+	Accounts []AccountTiming `json:"accounts"`
+}
+
+type AccountTiming struct {
+	Email    string `json:"email"`
+	FetchMs  int64  `json:"fetch_ms"`
+	Attempts int    `json:"attempts"`
+	Status   string `json:"status"`
+}
+
+func newTimings(meta *Meta, fetch, total time.Duration) *Timings {
+	t := &Timings{TotalMs: total.Milliseconds(), FetchMs: fetch.Milliseconds(), Accounts: []AccountTiming{}}
+	for _, a := range meta.Accounts {
+		t.Accounts = append(t.Accounts, AccountTiming{Email: a.Email, FetchMs: a.DurationMs, Attempts: a.Attempts, Status: a.Status})
+	}
+	return t
+}
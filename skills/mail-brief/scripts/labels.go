@@ -0,0 +1,213 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// --- Label Management ---
+
+type Label struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"` // system or user
+}
+
+type AccountLabels struct {
+	Email  string  `json:"email"`
+	Type   string  `json:"type"`
+	Labels []Label `json:"labels"`
+}
+
+type LabelAssignment struct {
+	ThreadID string `json:"thread_id"`
+	Error    string `json:"error,omitempty"`
+}
+
+type LabelsOutput struct {
+	Action   string            `json:"action"`
+	Accounts []AccountLabels   `json:"accounts,omitempty"`
+	Label    *Label            `json:"label,omitempty"`
+	Created  bool              `json:"created,omitempty"`
+	Assigned []LabelAssignment `json:"assigned,omitempty"`
+	Staged   *PendingAction    `json:"staged,omitempty"` // with --require-approval
+	Errors   []AccountError    `json:"errors,omitempty"`
+}
+
+func fetchLabels(accountEmail string) ([]Label, error) {
+	out, err := runGog("gmail", "labels", "list", "--json", fmt.Sprintf("--account=%s", accountEmail))
+	if err != nil {
+		return nil, err
+	}
+	var wrapped struct {
+		Labels []Label `json:"labels"`
+	}
+	if err := json.Unmarshal(out, &wrapped); err == nil {
+		if wrapped.Labels == nil {
+			wrapped.Labels = []Label{}
+		}
+		return wrapped.Labels, nil
+	}
+	labels := []Label{}
+	if err := json.Unmarshal(out, &labels); err != nil {
+		return nil, fmt.Errorf("unexpected JSON format from gog")
+	}
+	return labels, nil
+}
+
+func createLabel(accountEmail, name string) (Label, error) {
+	out, err := runGog("gmail", "labels", "create", name, "--json", fmt.Sprintf("--account=%s", accountEmail))
+	var label Label
+	if err == nil && (json.Unmarshal(out, &label) != nil || label.ID == "") {
+		err = fmt.Errorf("unexpected JSON format from gog")
+	}
+	recordAction("label_create", accountEmail, map[string]string{"label": name, "label_id": label.ID}, err)
+	if err != nil {
+		return Label{}, err
+	}
+	return label, nil
+}
+
+// findOrCreateLabel looks a label up by name (case-insensitively, as Gmail
+// does) and creates it when missing.
+func findOrCreateLabel(accountEmail, name string) (Label, bool, error) {
+	labels, err := fetchLabels(accountEmail)
+	if err != nil {
+		return Label{}, false, err
+	}
+	for _, l := range labels {
+		if strings.EqualFold(l.Name, name) {
+			return l, false, nil
+		}
+	}
+	label, err := createLabel(accountEmail, name)
+	return label, err == nil, err
+}
+
+// runLabels performs one of the list, create or assign actions. Creating and
+// assigning change a single mailbox, so they need exactly one account; with
+// stage set they are queued for --approve instead.
+func runLabels(accounts []Account, action, name string, threadIDs []string, stage bool) LabelsOutput {
+	output := LabelsOutput{Action: action}
+	fail := func(email string, err error) LabelsOutput {
+		output.Errors = append(output.Errors, AccountError{Email: email, Error: err.Error()})
+		return output
+	}
+
+	if action == "list" {
+		for _, account := range accounts {
+			labels, err := fetchLabels(account.Email)
+			if err != nil {
+				fail(account.Email, err)
+				continue
+			}
+			output.Accounts = append(output.Accounts, AccountLabels{Email: account.Email, Type: account.Type, Labels: labels})
+		}
+		return output
+	}
+
+	if action != "create" && action != "assign" {
+		return fail("", fmt.Errorf("unknown --labels action %q (want list, create or assign)", action))
+	}
+	if name == "" {
+		return fail("", fmt.Errorf("--labels %s requires --label", action))
+	}
+	if len(accounts) != 1 {
+		return fail("", fmt.Errorf("--labels %s needs exactly one account; pass --personal or --work", action))
+	}
+	account := accounts[0]
+	if action == "assign" && len(threadIDs) == 0 {
+		return fail(account.Email, fmt.Errorf("--labels assign requires --ids"))
+	}
+
+	if stage {
+		p, err := stageAction("labels", account.Email, map[string]string{"action": action, "label": name, "ids": strings.Join(threadIDs, ",")})
+		if err != nil {
+			return fail(account.Email, err)
+		}
+		output.Staged = &p
+		return output
+	}
+
+	label, created, err := findOrCreateLabel(account.Email, name)
+	if err != nil {
+		return fail(account.Email, err)
+	}
+	output.Label, output.Created = &label, created
+
+	if action == "assign" {
+		for _, id := range threadIDs {
+			a := LabelAssignment{ThreadID: id}
+			_, err := runGog("gmail", "thread", "modify", id, fmt.Sprintf("--add=%s", label.ID), fmt.Sprintf("--account=%s", account.Email))
+			recordAction("label_add", account.Email, map[string]string{"thread_id": id, "label": label.Name, "label_id": label.ID}, err)
+			if err != nil {
+				a.Error = err.Error()
+			}
+			output.Assigned = append(output.Assigned, a)
+		}
+	}
+	return output
+}
+
+// --- Label Filters ---
+
+// --label and --exclude-label narrow the brief to, or away from, Gmail
+// labels; both repeat. Promotions and Social are left out unless some
+// --exclude-label is given (--exclude-label=none keeps everything) or the
+// category is asked for with --label.
+
+var defaultExcludedLabels = []string{"CATEGORY_PROMOTIONS", "CATEGORY_SOCIAL"}
+
+// labelListFlag collects a repeatable label flag.
+type labelListFlag []string
+
+func (f *labelListFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *labelListFlag) Set(s string) error {
+	if s = strings.TrimSpace(s); s == "" {
+		return fmt.Errorf("empty label")
+	}
+	*f = append(*f, s)
+	return nil
+}
+
+// labelTerm turns a label name into Gmail search syntax: the inbox
+// categories have their own operator, and other labels are written with
+// spaces and slashes as dashes.
+func labelTerm(name string) string {
+	if rest, ok := strings.CutPrefix(strings.ToUpper(name), "CATEGORY_"); ok {
+		return "category:" + strings.ToLower(rest)
+	}
+	return "label:" + strings.NewReplacer(" ", "-", "/", "-").Replace(strings.ToLower(name))
+}
+
+// labelQuery builds the search terms for the label flags: any of include,
+// none of exclude.
+func labelQuery(include, exclude []string) string {
+	var any []string
+	for _, l := range include {
+		any = append(any, labelTerm(l))
+	}
+	if len(exclude) == 0 {
+		for _, l := range defaultExcludedLabels {
+			if !containsString(any, labelTerm(l)) {
+				exclude = append(exclude, l)
+			}
+		}
+	}
+	var terms []string
+	switch len(any) {
+	case 0:
+	case 1:
+		terms = append(terms, any[0])
+	default:
+		terms = append(terms, "("+strings.Join(any, " OR ")+")")
+	}
+	for _, l := range exclude {
+		if !strings.EqualFold(l, "none") {
+			terms = append(terms, "-"+labelTerm(l))
+		}
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestLabelQuery(t *testing.T) {
+	cases := []struct {
+		include, exclude []string
+		want             string
+	}{
+		{nil, nil, "-category:promotions -category:social"},
+		{[]string{"Finance"}, nil, "label:finance -category:promotions -category:social"},
+		{[]string{"Work/Project X", "CATEGORY_UPDATES"}, nil, "(label:work-project-x OR category:updates) -category:promotions -category:social"},
+		// Asking for a default-excluded category keeps it.
+		{[]string{"CATEGORY_PROMOTIONS"}, nil, "category:promotions -category:social"},
+		{nil, []string{"Newsletters"}, "-label:newsletters"},
+		{nil, []string{"none"}, ""},
+		{[]string{"Finance"}, []string{"NONE"}, "label:finance"},
+	}
+	for _, c := range cases {
+		if got := labelQuery(c.include, c.exclude); got != c.want {
+			t.Errorf("labelQuery(%q, %q) = %q, want %q", c.include, c.exclude, got, c.want)
+		}
+	}
+}
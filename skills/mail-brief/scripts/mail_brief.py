@@ -40,6 +40,12 @@ def parse_args():
         help="Work account email (optional, auto-discovers if not provided)"
     )
 
+    parser.add_argument(
+        "--imap-only",
+        action="store_true",
+        help="Fetch only the IMAP accounts in accounts.json (Gmail is read by the mail-brief binary)"
+    )
+
     # Date range specification (mutually exclusive)
     date_group = parser.add_mutually_exclusive_group()
     date_group.add_argument(
@@ -344,6 +350,8 @@ def resolve_accounts(args):
         - imap_accounts: List of IMAP account config dicts
     """
     gmail_accounts = []
+    if args.imap_only:
+        return gmail_accounts, load_imap_accounts()
 
     # If explicit Gmail accounts provided, use them
     if args.personal:
@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,7 +17,7 @@ import (
 
 type Account struct {
 	Email string `json:"email"`
-	Type  string `json:"type"`
+	Type  string `json:"type"` // personal, work or shared
 }
 
 type SimplifiedMessage struct {
@@ -26,12 +28,41 @@ type SimplifiedMessage struct {
 	Labels      []string `json:"labels"`
 	IsUnread    bool     `json:"is_unread"`
 	AccountType string   `json:"account_type"`
+
+	FirstContact bool `json:"first_contact,omitempty"`
+
+	Tags []string `json:"tags,omitempty"` // from --tag on the sender, see annotateSenders
+	Note string   `json:"note,omitempty"`
+
+	Snippet      string `json:"snippet,omitempty"` // with --snippet, see messagePreview
+	Confidential bool   `json:"confidential,omitempty"`
+
+	Suspicious        bool     `json:"suspicious,omitempty"`
+	SuspiciousReasons []string `json:"suspicious_reasons,omitempty"`
+
+	SubjectKey string `json:"subject_key,omitempty"`
+	FromKey    string `json:"from_key,omitempty"`
+
+	Summary      string `json:"summary,omitempty"`
+	SummaryError string `json:"summary_error,omitempty"`
+
+	id       string // Gmail message ID, for --alerts
+	threadID string // for --person
+	body     string // plain text, for --summarize-with and --action-items; never set on withheld confidential mail
 }
 
 type Output struct {
-	Accounts []Account           `json:"accounts"`
-	Messages []SimplifiedMessage `json:"messages"`
-	Errors   []AccountError      `json:"errors,omitempty"`
+	Accounts    []Account           `json:"accounts"`
+	Messages    []SimplifiedMessage `json:"messages"`
+	ActionItems []ActionItem        `json:"action_items,omitempty"`
+	Deliveries  []DeliveryFailure   `json:"delivery_failures,omitempty"`
+	Scheduled   []ScheduledItem     `json:"scheduled,omitempty"`
+	Errors      []AccountError      `json:"errors,omitempty"`
+	Meta        *Meta               `json:"meta,omitempty"`
+	Chart       string              `json:"chart,omitempty"` // SVG written by --chart
+	Alerts      []Alert             `json:"alerts,omitempty"`
+	Person      *PersonThreads      `json:"person,omitempty"`
+	Timings     *Timings            `json:"timings,omitempty"` // with --timings
 }
 
 type AccountError struct {
@@ -80,11 +111,21 @@ func discoverAccounts() []string {
 	return emails
 }
 
+// sharedMailboxNames are local parts that name a team inbox rather than a
+// person.
+var sharedMailboxNames = map[string]bool{
+	"support": true, "info": true, "help": true, "sales": true, "contact": true,
+	"hello": true, "team": true, "office": true, "billing": true, "hr": true,
+}
+
 func classifyAccount(email string) string {
 	parts := strings.SplitN(email, "@", 2)
 	if len(parts) < 2 {
 		return "work"
 	}
+	if sharedMailboxNames[strings.ToLower(parts[0])] {
+		return "shared"
+	}
 	domain := strings.ToLower(parts[1])
 	if personalDomains[domain] {
 		return "personal"
@@ -92,7 +133,11 @@ func classifyAccount(email string) string {
 	return "work"
 }
 
-func resolveAccounts(personal, work string) []Account {
+// resolveAccounts builds the account list from the flags, falling back to
+// every gog account. Shared mailboxes (support@, info@) are added on top:
+// gog reaches them through delegation or a service account impersonating
+// the mailbox, so they are fetched like any other account.
+func resolveAccounts(personal, work string, shared []string, classifyCommand string) ([]Account, error) {
 	var accounts []Account
 	if personal != "" {
 		accounts = append(accounts, Account{Email: personal, Type: "personal"})
@@ -100,20 +145,30 @@ func resolveAccounts(personal, work string) []Account {
 	if work != "" {
 		accounts = append(accounts, Account{Email: work, Type: "work"})
 	}
-	if len(accounts) > 0 {
-		return accounts
+	if len(accounts) == 0 {
+		discovered, err := classifyAccounts(discoverAccounts(), classifyCommand)
+		if err != nil {
+			return nil, err
+		}
+		accounts = discovered
 	}
-	for _, email := range discoverAccounts() {
-		accounts = append(accounts, Account{Email: email, Type: classifyAccount(email)})
+	for _, email := range shared {
+		dup := false
+		for i := range accounts {
+			if strings.EqualFold(accounts[i].Email, email) {
+				accounts[i].Type, dup = "shared", true
+			}
+		}
+		if !dup {
+			accounts = append(accounts, Account{Email: email, Type: "shared"})
+		}
 	}
-	return accounts
+	return accounts, nil
 }
 
 // --- Query Building ---
 
-func buildGmailQuery(today, yesterday, thisWeek, lastWeek bool, date string) string {
-	now := time.Now()
-
+func buildGmailQuery(now time.Time, today, yesterday, thisWeek, lastWeek bool, date string, weekStart time.Weekday) string {
 	if date != "" {
 		targetDate, err := time.Parse("2006-01-02", date)
 		if err == nil {
@@ -125,20 +180,17 @@ func buildGmailQuery(today, yesterday, thisWeek, lastWeek bool, date string) str
 	}
 
 	if lastWeek {
-		weekday := now.Weekday() // Sun=0..Sat=6
-		thisSunday := now.AddDate(0, 0, -int(weekday))
-		lastSunday := thisSunday.AddDate(0, 0, -7)
+		first := startOfWeek(now, weekStart)
 		return fmt.Sprintf("after:%s before:%s",
-			lastSunday.Format("2006/01/02"),
-			thisSunday.Format("2006/01/02"))
+			first.AddDate(0, 0, -7).Format("2006/01/02"),
+			first.Format("2006/01/02"))
 	}
 
 	if thisWeek {
-		weekday := now.Weekday() // Sun=0..Sat=6
-		thisSunday := now.AddDate(0, 0, -int(weekday))
+		first := startOfWeek(now, weekStart)
 		tomorrow := now.AddDate(0, 0, 1)
 		return fmt.Sprintf("after:%s before:%s",
-			thisSunday.Format("2006/01/02"),
+			first.Format("2006/01/02"),
 			tomorrow.Format("2006/01/02"))
 	}
 
@@ -154,10 +206,21 @@ func buildGmailQuery(today, yesterday, thisWeek, lastWeek bool, date string) str
 
 // --- Message Fetching ---
 
-func fetchMessages(accountEmail, query string) ([]map[string]interface{}, error) {
-	args := []string{"gmail", "messages", "search", query, "--json", "--max=50", fmt.Sprintf("--account=%s", accountEmail)}
+// runGog runs a gog command and returns its stdout, turning a failed run into
+// an error carrying gog's stderr.
+func runGog(args ...string) ([]byte, error) {
+	return runGogContext(context.Background(), args...)
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// runGogContext runs gog under ctx once a gogPool slot is free, still
+// capping a single call at 30s.
+func runGogContext(ctx context.Context, args ...string) ([]byte, error) {
+	release, err := gogPool.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "gog", args...)
@@ -171,33 +234,112 @@ func fetchMessages(accountEmail, query string) ([]map[string]interface{}, error)
 		}
 		return nil, fmt.Errorf("%s", errMsg)
 	}
+	return out, nil
+}
 
-	var asMap map[string]interface{}
-	if err := json.Unmarshal(out, &asMap); err == nil {
-		if messagesRaw, ok := asMap["messages"]; ok {
-			if messagesSlice, ok := messagesRaw.([]interface{}); ok {
-				return toMapSlice(messagesSlice), nil
-			}
-		}
-		return nil, nil
+func fetchMessages(ctx context.Context, accountEmail, query string, max int) ([]GogMessage, error) {
+	list, err := fetchMessageList(ctx, accountEmail, query, max)
+	return list.Items, err
+}
+
+// fetchMessageList is fetchMessages with gog's whole reply, for the
+// Malformed count.
+func fetchMessageList(ctx context.Context, accountEmail, query string, max int) (gogList[GogMessage], error) {
+	args := []string{"gmail", "messages", "search", query, "--json", fmt.Sprintf("--max=%d", max), fmt.Sprintf("--account=%s", accountEmail)}
+
+	var list gogList[GogMessage]
+	err := streamGog(ctx, func(r io.Reader) error {
+		var err error
+		list, err = decodeList[GogMessage](r, "messages")
+		return err
+	}, args...)
+	if err != nil {
+		return gogList[GogMessage]{}, err
 	}
+	return list, nil
+}
 
-	var asSlice []interface{}
-	if err := json.Unmarshal(out, &asSlice); err == nil {
-		return toMapSlice(asSlice), nil
+// fetchMessage gets one message in full. Search results carry only Gmail's
+// snippet; this one has the body or the MIME parts.
+func fetchMessage(ctx context.Context, accountEmail, id string) (GogMessage, error) {
+	out, err := runGogContext(ctx, "gmail", "get", id, "--json", fmt.Sprintf("--account=%s", accountEmail))
+	if err != nil {
+		return GogMessage{}, err
 	}
 
-	return nil, fmt.Errorf("unexpected JSON format from gog")
+	// gog may wrap the Gmail message resource in a "message" key.
+	var data struct {
+		GogMessage
+		Message *GogMessage `json:"message"`
+	}
+	if err := json.Unmarshal(out, &data); err != nil {
+		return GogMessage{}, fmt.Errorf("unexpected JSON format from gog")
+	}
+	if data.Message != nil {
+		return *data.Message, nil
+	}
+	return data.GogMessage, nil
 }
 
-func toMapSlice(raw []interface{}) []map[string]interface{} {
-	result := make([]map[string]interface{}, 0, len(raw))
-	for _, item := range raw {
-		if m, ok := item.(map[string]interface{}); ok {
-			result = append(result, m)
+// fetchBodies fills in the body of each search result that came without
+// one, for --summarize-with, --action-items and --snippet=N. Confidential
+// mail is left alone unless includeConfidential. The fetches take gogPool
+// slots like any gog call; a message whose fetch fails keeps its snippet.
+func fetchBodies(ctx context.Context, accountEmail string, messages []GogMessage, includeConfidential bool) {
+	parallel(len(messages), func(i int) {
+		m := messages[i]
+		if m.ID == "" || messageBody(m) != "" || (isConfidential(m) && !includeConfidential) {
+			return
 		}
-	}
-	return result
+		full, err := fetchMessage(ctx, accountEmail, m.ID)
+		if err != nil {
+			return
+		}
+		messages[i].Body, messages[i].MimeType, messages[i].Parts, messages[i].Payload = full.Body, full.MimeType, full.Parts, full.Payload
+	})
+}
+
+type fetchResult struct {
+	messages     []GogMessage
+	scheduled    []ScheduledItem
+	scheduledErr error
+	meta         AccountMeta
+	err          error
+}
+
+// fetchAllMessages fetches every account at once, each as its own job (see
+// runAccountJob); gogPool bounds the gog processes actually running, so a
+// slow account no longer holds up the others. Results are indexed like
+// accounts so output order does not depend on which account answers first.
+// Bodies are fetched within the account's job when msgOpts needs them.
+func fetchAllMessages(accounts []Account, query string, msgOpts messageOptions, opts jobOptions, health map[string]accountHealth) []fetchResult {
+	results := make([]fetchResult, len(accounts))
+	parallel(len(accounts), func(i int) {
+		account := accounts[i]
+		var r fetchResult
+		var malformed int
+		r.meta, r.err = runAccountJob(account.Email, optionsFor(opts, health[account.Email]), func(ctx context.Context) error {
+			var wg sync.WaitGroup
+			if msgOpts.Scheduled {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					r.scheduled, r.scheduledErr = fetchScheduled(ctx, account)
+				}()
+			}
+			list, err := fetchMessageList(ctx, account.Email, query, 50)
+			r.messages, malformed = list.Items, list.Malformed
+			if err == nil && msgOpts.needsBody() {
+				fetchBodies(ctx, account.Email, r.messages, msgOpts.IncludeConfidential)
+			}
+			wg.Wait()
+			return err
+		})
+		r.meta.Malformed = malformed
+		r.meta.Degraded = health[account.Email].Failures >= degradeAfter
+		results[i] = r
+	})
+	return results
 }
 
 // --- Message Processing ---
@@ -219,48 +361,18 @@ func parseFrom(raw string) (string, string) {
 	return raw, raw
 }
 
-func getString(m map[string]interface{}, key string) string {
-	if v, ok := m[key]; ok {
-		if s, ok := v.(string); ok {
-			return s
-		}
-	}
-	return ""
-}
-
-func getStringSlice(m map[string]interface{}, key string) []string {
-	if v, ok := m[key]; ok {
-		if arr, ok := v.([]interface{}); ok {
-			result := make([]string, 0, len(arr))
-			for _, item := range arr {
-				if s, ok := item.(string); ok {
-					result = append(result, s)
-				}
-			}
-			return result
-		}
-	}
-	return nil
-}
-
-func simplifyMessage(msg map[string]interface{}, accountType string) SimplifiedMessage {
-	subject := getString(msg, "subject")
+func simplifyMessage(msg GogMessage, accountType string) SimplifiedMessage {
+	subject := msg.Subject
 	if subject == "" {
 		subject = "(No subject)"
 	}
 
-	fromRaw := getString(msg, "from")
-	fromName, fromEmail := parseFrom(fromRaw)
-
-	labels := getStringSlice(msg, "labels")
-	if labels == nil {
-		labels = []string{}
-	}
+	fromName, fromEmail := parseFrom(msg.From)
 
 	// Filter out UNREAD from labels (already captured in IsUnread)
-	filtered := make([]string, 0, len(labels))
+	filtered := make([]string, 0, len(msg.Labels))
 	isUnread := false
-	for _, label := range labels {
+	for _, label := range msg.Labels {
 		if label == "UNREAD" {
 			isUnread = true
 		} else {
@@ -269,7 +381,7 @@ func simplifyMessage(msg map[string]interface{}, accountType string) SimplifiedM
 	}
 
 	return SimplifiedMessage{
-		Date:        getString(msg, "date"),
+		Date:        msg.Date,
 		Subject:     subject,
 		FromName:    fromName,
 		FromEmail:   fromEmail,
@@ -279,65 +391,478 @@ func simplifyMessage(msg map[string]interface{}, accountType string) SimplifiedM
 	}
 }
 
+// messageOptions are the flags that shape each message of a brief.
+type messageOptions struct {
+	Unread, Starred     bool
+	Snippet             snippetFlag
+	IncludeConfidential bool
+	Body                bool // keep the text for --summarize-with and --action-items
+	Normalize           bool
+	Person              string
+	Scheduled           bool
+	MyDomains           []string
+	Accounts            []Account
+	Now                 time.Time
+}
+
+// needsBody reports whether the brief reads message bodies, which search
+// results do not include.
+func (o messageOptions) needsBody() bool {
+	return o.Body || o.Snippet > 0
+}
+
+// accountMessages is what one account's mail contributes to a brief.
+type accountMessages struct {
+	Messages   []SimplifiedMessage
+	Files      []SharedFile // shared by --person
+	Deliveries []DeliveryFailure
+	Scheduled  []ScheduledItem
+}
+
+// processMessages turns one account's raw messages into the brief's
+// messages and collects the bounces, shared files and meetings found in
+// them.
+func processMessages(raw []GogMessage, accountType string, opts messageOptions) accountMessages {
+	var out accountMessages
+	for _, m := range raw {
+		msg := simplifyMessage(m, accountType)
+		// The search already asks for these, but a cached fallback may
+		// predate a message being read or unstarred.
+		if (opts.Unread && !msg.IsUnread) || (opts.Starred && !containsString(msg.Labels, "STARRED")) {
+			continue
+		}
+		msg.id, msg.threadID = m.ID, m.ThreadID
+		msg.Confidential = isConfidential(m)
+		readable := !msg.Confidential || opts.IncludeConfidential
+		if opts.Snippet != 0 && readable {
+			msg.Snippet = messagePreview(m, opts.Snippet)
+		}
+		if opts.Body && readable {
+			msg.body = messageText(m)
+		}
+		if reasons := suspiciousReasons(m, msg, opts.MyDomains); len(reasons) > 0 {
+			msg.Suspicious, msg.SuspiciousReasons = true, reasons
+		}
+		if opts.Normalize {
+			msg.Subject = normalizeText(msg.Subject)
+			msg.FromName = normalizeText(msg.FromName)
+			msg.SubjectKey = subjectKey(msg.Subject)
+			msg.FromKey = normalizeKey(msg.FromName)
+			if msg.FromKey == "" {
+				msg.FromKey = normalizeKey(msg.FromEmail)
+			}
+		}
+		out.Messages = append(out.Messages, msg)
+		if opts.Person != "" && readable {
+			out.Files = append(out.Files, sharedFiles(m, msg, opts.Person, opts.Accounts)...)
+		}
+		if failure, ok := detectDeliveryFailure(m, msg); ok {
+			out.Deliveries = append(out.Deliveries, failure)
+		}
+		if opts.Scheduled {
+			if item, ok := futureMeetingItem(msg, opts.Now); ok {
+				out.Scheduled = append(out.Scheduled, item)
+			}
+		}
+	}
+	return out
+}
+
 // --- Main ---
 
 func main() {
+	runStart := time.Now()
 	personal := flag.String("personal", "", "Personal account email")
 	work := flag.String("work", "", "Work account email")
+	sharedSpec := flag.String("shared", "", "Comma-separated shared/delegated mailboxes to include (e.g. support@corp.com)")
 	today := flag.Bool("today", false, "Today's messages (default)")
 	yesterday := flag.Bool("yesterday", false, "Yesterday's messages")
-	thisWeek := flag.Bool("this-week", false, "This week (Sun-Sat)")
-	lastWeek := flag.Bool("last-week", false, "Last week (Sun-Sat)")
+	thisWeek := flag.Bool("this-week", false, "This week (Sun-Sat, or Mon-Sun with --week-start=mon)")
+	lastWeek := flag.Bool("last-week", false, "Last week (Sun-Sat, or Mon-Sun with --week-start=mon)")
+	weekStartFlag := flag.String("week-start", "", "First day of the week: mon or sun (default: config week_start, then $"+weekStartEnv+", then sun)")
 	date := flag.String("date", "", "Specific date (YYYY-MM-DD)")
+	actionItems := flag.Bool("action-items", false, "Extract explicit asks and deadlines into action_items")
+	threadID := flag.String("thread", "", "Emit a chronological timeline of one thread ID")
+	vacation := flag.Bool("vacation", false, "Report each account's vacation responder status")
+	accountUsage := flag.Bool("account-usage", false, "Report Gmail/Drive storage usage per account")
+	backfill := flag.Bool("backfill", false, "Read mail history since --from into the sender store, so first-contact flags work from the first brief")
+	backfillFrom := flag.String("from", "", "Start date (YYYY-MM-DD) for --backfill")
+	inboxStats := flag.Bool("inbox-stats", false, "Report inbox size, oldest unread, and unread-by-label stats")
+	normalize := flag.Bool("normalize", false, "Normalize subjects/senders (NFC, width folding) and add grouping keys")
+	scheduled := flag.Bool("scheduled", false, "Report scheduled sends and meeting mail about future dates")
+	summarizeWith := flag.String("summarize-with", "", "Pipe each message through CMD and attach its output as summary")
+	var snippet snippetFlag
+	flag.Var(&snippet, "snippet", "Include Gmail's snippet of each message, or with --snippet=N the first N characters of its body")
+	includeConfidential := flag.Bool("include-confidential", false, "Include snippets and summaries of confidential/encrypted messages")
+	icsReply := flag.String("ics-reply", "", "Build an ICS REPLY to the invite in this thread ID (non-Google organizers)")
+	rsvp := flag.String("rsvp", "accept", "Answer for --ics-reply: accept, decline or tentative")
+	send := flag.Bool("send", false, "With --ics-reply, email the reply to the organizer")
+	labelsAction := flag.String("labels", "", "Label action: list, create or assign")
+	var labelNames, excludeLabels labelListFlag
+	flag.Var(&labelNames, "label", "Only mail with this Gmail label (repeatable, any of them); with --labels create/assign, the label to act on")
+	flag.Var(&excludeLabels, "exclude-label", "Skip mail with this Gmail label (repeatable; default CATEGORY_PROMOTIONS and CATEGORY_SOCIAL, none to keep all)")
+	threadIDs := flag.String("ids", "", "Comma-separated thread IDs for --labels assign")
+	accountTimeout := flag.Duration("account-timeout", 60*time.Second, "Time budget per account, retries included")
+	unread := flag.Bool("unread", false, "Only unread mail")
+	starred := flag.Bool("starred", false, "Only starred mail")
+	search := flag.String("query", "", "Gmail search terms ANDed with the date window, e.g. \"from:boss has:attachment\"")
+	person := flag.String("person", "", "Only mail exchanged with this email, summed up by thread (the last 30 days unless a date flag is given)")
+	retries := flag.Int("retries", 1, "Retries per account after a failed fetch")
+	concurrency := flag.Int("concurrency", defaultConcurrency, "Max gog processes in flight across accounts and lookups")
+	timings := flag.Bool("timings", false, "Add a timings block: wall time of the run and the fetch, and each account's share")
+	configPath := flag.String("config", defaultConfigPath(), "Path to config.json")
+	alerts := flag.Bool("alerts", false, "Report new mail matching the config's alert_rules since the last --alerts run")
+	chartPath := flag.String("chart", "", "Write an SVG chart of messages per hour to this path")
+	fieldSpec := flag.String("fields", "", "Comma-separated message fields to emit (e.g. subject,from_email)")
+	audit := flag.Bool("audit", false, "List the audit log of sends and label changes")
+	undo := flag.String("undo", "", "Reverse a logged action: LAST or an audit entry ID")
+	requireApproval := flag.Bool("require-approval", false, "Stage sends and label changes for --approve instead of performing them")
+	pending := flag.Bool("pending", false, "List actions staged by --require-approval")
+	approve := flag.String("approve", "", "Perform staged actions: ALL or a pending action ID")
+	scopes := flag.Bool("scopes", false, "Report the OAuth services and scopes gog holds per account")
+	tag := flag.String("tag", "", "Annotate a sender for later briefs: EMAIL:tag[,tag...]")
+	note := flag.String("note", "", "With --tag, attach this note to the sender")
+	untag := flag.String("untag", "", "Remove tags: EMAIL:tag[,tag...], or EMAIL to drop its tags and note")
+	listTags := flag.Bool("tags", false, "List sender annotations made with --tag")
+	exportBundle := flag.String("export-bundle", "", "Write the config, accounts, tags and notes of both brief tools to this encrypted file (passphrase from $"+bundlePassphraseEnv+")")
+	importBundle := flag.String("import-bundle", "", "Restore the files of a bundle made with --export-bundle, replacing existing ones")
+	includeState := flag.Bool("include-state", false, "With --export-bundle, add the rest of the local state (pending actions, audit log, snapshots)")
+	readOnly := flag.Bool("read-only", readOnlyFromEnv(), "Refuse every write action (also set by BRIEF_READ_ONLY=1)")
+	pprofSpec := flag.String("pprof", "", "Profile the run: a directory for cpu.pprof and heap.pprof, or host:port to serve net/http/pprof")
 	flag.Parse()
+	gogPool = newWorkerPool(*concurrency)
+
+	if *pprofSpec != "" {
+		stop, err := startProfiling(*pprofSpec)
+		if err != nil {
+			writeJSON(map[string]string{"error": fmt.Sprintf("--pprof: %v", err)})
+			os.Exit(1)
+		}
+		defer stop()
+	}
+
+	if *readOnly {
+		var refused []string
+		if *send && !*requireApproval {
+			refused = append(refused, "--send")
+		}
+		if (*labelsAction == "create" || *labelsAction == "assign") && !*requireApproval {
+			refused = append(refused, "--labels "+*labelsAction)
+		}
+		if *undo != "" {
+			refused = append(refused, "--undo")
+		}
+		if *approve != "" {
+			refused = append(refused, "--approve")
+		}
+		if len(refused) > 0 {
+			writeJSON(map[string]string{"error": fmt.Sprintf("Read-only mode refuses %s", strings.Join(refused, ", "))})
+			os.Exit(1)
+		}
+	}
+
+	if *audit {
+		writeJSON(runAuditList())
+		return
+	}
+	if *undo != "" {
+		writeJSON(runUndo(*undo))
+		return
+	}
+	if *pending {
+		writeJSON(PendingOutput{Pending: loadPending()})
+		return
+	}
+	if *tag != "" {
+		writeJSON(runTag(*tag, *note, time.Now()))
+		return
+	}
+	if *untag != "" {
+		writeJSON(runUntag(*untag))
+		return
+	}
+	if *listTags {
+		writeJSON(AnnotationsOutput{Annotations: loadAnnotations()})
+		return
+	}
+	if *approve != "" {
+		writeJSON(runApprove(*approve))
+		return
+	}
+	if *exportBundle != "" || *importBundle != "" {
+		var output BundleOutput
+		var err error
+		if *exportBundle != "" {
+			output, err = runExportBundle(*exportBundle, *includeState, time.Now())
+		} else {
+			output, err = runImportBundle(*importBundle)
+		}
+		if err != nil {
+			writeJSON(map[string]string{"error": err.Error()})
+			os.Exit(1)
+		}
+		writeJSON(output)
+		return
+	}
+
+	var backfillStart time.Time
+	if *backfill {
+		var err error
+		backfillStart, err = time.ParseInLocation("2006-01-02", *backfillFrom, time.Local)
+		if err != nil || backfillStart.After(time.Now()) {
+			writeJSON(map[string]string{"error": "--backfill needs --from YYYY-MM-DD, not in the future"})
+			os.Exit(1)
+		}
+	}
 
 	// Default to today when no date flag is given
-	if !*today && !*yesterday && !*thisWeek && !*lastWeek && *date == "" {
+	datePicked := *today || *yesterday || *thisWeek || *lastWeek || *date != ""
+	if !datePicked {
 		*today = true
 	}
 
-	accounts := resolveAccounts(*personal, *work)
+	var fields []string
+	if *fieldSpec != "" {
+		var err error
+		if fields, err = parseFields(*fieldSpec, SimplifiedMessage{}); err != nil {
+			writeJSON(map[string]string{"error": err.Error()})
+			os.Exit(1)
+		}
+	}
+
+	var shared []string
+	for _, email := range strings.Split(*sharedSpec, ",") {
+		if email = strings.TrimSpace(email); email != "" {
+			shared = append(shared, email)
+		}
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		writeJSON(map[string]string{"error": err.Error()})
+		os.Exit(1)
+	}
+	weekStart, err := resolveWeekStart(*weekStartFlag, cfg.WeekStart)
+	if err != nil {
+		writeJSON(map[string]string{"error": err.Error()})
+		os.Exit(1)
+	}
+	accounts, err := resolveAccounts(*personal, *work, shared, cfg.ClassifyCommand)
+	if err != nil {
+		writeJSON(map[string]string{"error": err.Error()})
+		os.Exit(1)
+	}
 	if len(accounts) == 0 {
 		errObj := map[string]string{
 			"error": "No accounts found. Use --personal/--work or configure gog auth.",
 		}
-		enc := json.NewEncoder(os.Stdout)
-		enc.SetIndent("", "  ")
-		enc.SetEscapeHTML(false)
-		enc.Encode(errObj)
+		writeJSON(errObj)
 		os.Exit(1)
 	}
 
-	query := buildGmailQuery(*today, *yesterday, *thisWeek, *lastWeek, *date)
+	if *scopes {
+		writeJSON(runScopes(accounts))
+		return
+	}
+
+	if *inboxStats {
+		writeJSON(runInboxStats(accounts))
+		return
+	}
+
+	if *backfill {
+		writeJSON(runBackfill(accounts, backfillStart, time.Now(), backfillPause))
+		return
+	}
+
+	if *accountUsage {
+		writeJSON(runUsage(accounts))
+		return
+	}
+
+	if *vacation {
+		writeJSON(runVacation(accounts))
+		return
+	}
+
+	if *threadID != "" {
+		writeJSON(runThread(accounts, *threadID, *includeConfidential))
+		return
+	}
+
+	if *icsReply != "" {
+		writeJSON(runInviteReply(accounts, *icsReply, *rsvp, *send, *requireApproval))
+		return
+	}
+
+	if *labelsAction != "" {
+		var ids []string
+		for _, id := range strings.Split(*threadIDs, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				ids = append(ids, id)
+			}
+		}
+		if len(labelNames) > 1 {
+			writeJSON(LabelsOutput{Action: *labelsAction, Errors: []AccountError{{Error: fmt.Sprintf("--labels %s takes a single --label", *labelsAction)}}})
+			return
+		}
+		writeJSON(runLabels(accounts, *labelsAction, labelNames.String(), ids, *requireApproval))
+		return
+	}
+
+	now, err := currentTime()
+	if err != nil {
+		writeJSON(map[string]string{"error": err.Error()})
+		os.Exit(1)
+	}
+	query := buildGmailQuery(now, *today, *yesterday, *thisWeek, *lastWeek, *date, weekStart)
+	if *person != "" {
+		if !datePicked {
+			query = personLookback
+		}
+		query = personQuery(*person) + " " + query
+	}
+	if q := strings.TrimSpace(*search); q != "" {
+		// Grouped so an OR inside cannot escape the date window.
+		query = "(" + q + ") " + query
+	}
+	if q := labelQuery(labelNames, excludeLabels); q != "" {
+		query = q + " " + query
+	}
+	if *starred {
+		query = "is:starred " + query
+	}
+	if *unread {
+		query = "is:unread " + query
+	}
 
 	var allMessages []SimplifiedMessage
+	var messageAccounts []string // account email of each message, for classify_command
+	var deliveryFailures []DeliveryFailure
+	var scheduledItems []ScheduledItem
+	var personFiles []SharedFile
 	var errors []AccountError
-
-	for _, account := range accounts {
-		rawMessages, err := fetchMessages(account.Email, query)
+	meta := &Meta{}
+	jobOpts := jobOptions{Timeout: *accountTimeout, Retries: *retries}
+	myDomains := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		myDomains = append(myDomains, emailDomain(a.Email))
+	}
+
+	msgOpts := messageOptions{
+		Unread:              *unread,
+		Starred:             *starred,
+		Snippet:             snippet,
+		IncludeConfidential: *includeConfidential,
+		Body:                *summarizeWith != "" || *actionItems,
+		Normalize:           *normalize,
+		Person:              *person,
+		Scheduled:           *scheduled,
+		MyDomains:           myDomains,
+		Accounts:            accounts,
+		Now:                 now,
+	}
+	health := loadHealth()
+	fetchStart := time.Now()
+	results := fetchAllMessages(accounts, query, msgOpts, jobOpts, health)
+	fetchDuration := time.Since(fetchStart)
+	for i, account := range accounts {
+		rawMessages, items, scheduledErr := results[i].messages, results[i].scheduled, results[i].scheduledErr
+		accountMeta, err := results[i].meta, results[i].err
+		recordHealth(health, account.Email, err, now)
+		if err == nil {
+			saveFallback(account.Email, query, rawMessages, *includeConfidential, now)
+		} else if cached, ok := loadFallback(account.Email, query); ok {
+			rawMessages, accountMeta.Status, accountMeta.CachedAt = cached.Items, "cached", cached.FetchedAt
+			errors = append(errors, AccountError{Email: account.Email, Error: fmt.Sprintf("%v (showing messages cached at %s)", err, cached.FetchedAt)})
+			err = nil
+		}
+		meta.Accounts = append(meta.Accounts, accountMeta)
 		if err != nil {
 			errors = append(errors, AccountError{Email: account.Email, Error: err.Error()})
 			continue
 		}
-		for _, m := range rawMessages {
-			allMessages = append(allMessages, simplifyMessage(m, account.Type))
+		found := processMessages(rawMessages, account.Type, msgOpts)
+		allMessages = append(allMessages, found.Messages...)
+		for range found.Messages {
+			messageAccounts = append(messageAccounts, account.Email)
 		}
+		personFiles = append(personFiles, found.Files...)
+		deliveryFailures = append(deliveryFailures, found.Deliveries...)
+		scheduledItems = append(scheduledItems, found.Scheduled...)
+		if scheduledErr != nil {
+			errors = append(errors, AccountError{Email: account.Email, Error: scheduledErr.Error()})
+		}
+		scheduledItems = append(scheduledItems, items...)
+	}
+	saveState("health.json", health)
+	if err := classifyMessages(allMessages, messageAccounts, cfg.ClassifyCommand); err != nil {
+		errors = append(errors, AccountError{Error: err.Error()})
 	}
 
 	if allMessages == nil {
 		allMessages = []SimplifiedMessage{}
 	}
+	markFirstContacts(allMessages, now)
+	annotateSenders(allMessages)
+
+	if *summarizeWith != "" {
+		summarizeMessages(allMessages, *summarizeWith, *includeConfidential)
+	}
 
 	output := Output{
-		Accounts: accounts,
-		Messages: allMessages,
+		Accounts:   accounts,
+		Messages:   allMessages,
+		Deliveries: deliveryFailures,
+		Scheduled:  scheduledItems,
+		Meta:       meta,
+	}
+	if *actionItems {
+		for _, m := range allMessages {
+			ref, ok := parseMessageDate(m.Date)
+			if !ok {
+				ref = now
+			}
+			for _, item := range extractActionItems(m.Subject+"\n"+m.body, ref) {
+				item.Subject = m.Subject
+				item.FromEmail = m.FromEmail
+				item.AccountType = m.AccountType
+				output.ActionItems = append(output.ActionItems, item)
+			}
+		}
+	}
+	if *person != "" {
+		output.Person = summarizePerson(allMessages, *person, accounts, personFiles)
 	}
 	if len(errors) > 0 {
 		output.Errors = errors
 	}
+	if *alerts {
+		output.Alerts = checkAlerts(allMessages, cfg.AlertRules, now)
+	}
+	if *timings {
+		output.Timings = newTimings(meta, fetchDuration, time.Since(runStart))
+	}
+	if *chartPath != "" {
+		if err := writeChart(*chartPath, mailVolumeChart(allMessages)); err != nil {
+			writeJSON(map[string]string{"error": fmt.Sprintf("Writing chart: %v", err)})
+			os.Exit(1)
+		}
+		output.Chart = *chartPath
+	}
+
+	if fields != nil {
+		writeJSON(struct {
+			Output
+			Messages []map[string]interface{} `json:"messages"`
+		}{output, projectFields(output.Messages, fields)})
+		return
+	}
+	writeJSON(output)
+}
 
+func writeJSON(v interface{}) {
 	enc := json.NewEncoder(os.Stdout)
 	enc.SetIndent("", "  ")
 	enc.SetEscapeHTML(false)
-	enc.Encode(output)
+	enc.Encode(v)
 }
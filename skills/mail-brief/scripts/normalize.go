@@ -0,0 +1,112 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// --- Text Normalization ---
+
+// Hangul conjoining jamo ranges and syllable arithmetic from the Unicode
+// standard (section 3.12). Mail clients on macOS often send decomposed
+// (NFD) Korean, which looks identical but compares unequal.
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+var (
+	latinAccented = []rune("ÀÁÂÃÄÅàáâãäåÇçÈÉÊËèéêëÌÍÎÏìíîïÑñÒÓÔÕÖØòóôõöøÙÚÛÜùúûüÝýÿ")
+	latinBase     = []rune("AAAAAAaaaaaaCcEEEEeeeeIIIIiiiiNnOOOOOOooooooUUUUuuuuYyy")
+	latinFold     = map[rune]rune{}
+
+	replyPrefixPattern = regexp.MustCompile(`(?i)^\s*((re|fw|fwd|aw|wg|sv|답장|회신|전달)\s*(\[\d+\])?\s*:\s*)+`)
+)
+
+func init() {
+	for i, r := range latinAccented {
+		latinFold[r] = latinBase[i]
+	}
+}
+
+// composeHangul applies canonical composition to Hangul jamo, which is the
+// part of NFC that matters for Korean text.
+func composeHangul(s string) string {
+	in := []rune(s)
+	out := make([]rune, 0, len(in))
+	for _, r := range in {
+		if n := len(out); n > 0 {
+			last := out[n-1]
+			// L + V -> LV syllable
+			if l, v := last-hangulLBase, r-hangulVBase; l >= 0 && l < hangulLCount && v >= 0 && v < hangulVCount {
+				out[n-1] = hangulSBase + (l*hangulVCount+v)*hangulTCount
+				continue
+			}
+			// LV + T -> LVT syllable
+			if sIdx, t := last-hangulSBase, r-hangulTBase; sIdx >= 0 && sIdx < hangulSCount && sIdx%hangulTCount == 0 && t > 0 && t < hangulTCount {
+				out[n-1] = last + t
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// foldWidth maps full-width ASCII variants and the ideographic space to their
+// ASCII equivalents.
+func foldWidth(r rune) rune {
+	switch {
+	case r >= 0xFF01 && r <= 0xFF5E:
+		return r - 0xFEE0
+	case r == 0x3000:
+		return ' '
+	}
+	return r
+}
+
+// normalizeText composes Hangul and folds full-width characters so visually
+// identical strings compare equal. It keeps case and accents.
+func normalizeText(s string) string {
+	return strings.Map(foldWidth, composeHangul(s))
+}
+
+// normalizeKey builds a grouping key: normalized, case-folded, accent-folded,
+// with combining marks dropped and whitespace collapsed.
+func normalizeKey(s string) string {
+	s = strings.ToLower(normalizeText(s))
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		if base, ok := latinFold[r]; ok {
+			r = unicode.ToLower(base)
+		}
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if unicode.IsSpace(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// subjectKey is normalizeKey with reply/forward prefixes removed, so a thread's
+// replies group with the original message.
+func subjectKey(subject string) string {
+	return normalizeKey(replyPrefixPattern.ReplaceAllString(normalizeText(subject), ""))
+}
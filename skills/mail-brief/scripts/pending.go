@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Approval Gate ---
+
+// With --require-approval, write actions are not performed but staged in
+// pending.json in the state directory. --pending lists them and --approve
+// runs them, so a person signs off between triage and the mailbox changing.
+
+type PendingAction struct {
+	ID      string            `json:"id"`
+	Staged  string            `json:"staged"`
+	Action  string            `json:"action"` // labels or send
+	Account string            `json:"account"`
+	Params  map[string]string `json:"params"`
+}
+
+type ApprovalResult struct {
+	PendingAction
+	Done  bool   `json:"done"`
+	Error string `json:"error,omitempty"`
+}
+
+type PendingOutput struct {
+	Pending  []PendingAction  `json:"pending"`
+	Approved []ApprovalResult `json:"approved,omitempty"`
+	Errors   []AccountError   `json:"errors,omitempty"`
+}
+
+func loadPending() []PendingAction {
+	pending := []PendingAction{}
+	loadState("pending.json", &pending)
+	return pending
+}
+
+// stageAction queues an action for approval and returns it.
+func stageAction(action, account string, params map[string]string) (PendingAction, error) {
+	now := time.Now()
+	p := PendingAction{
+		ID:      strconv.FormatInt(now.UnixNano(), 36),
+		Staged:  now.Format(time.RFC3339),
+		Action:  action,
+		Account: account,
+		Params:  params,
+	}
+	return p, saveState("pending.json", append(loadPending(), p))
+}
+
+// executePending performs a staged action the way the original command would
+// have without --require-approval.
+func executePending(p PendingAction) error {
+	account := Account{Email: p.Account}
+	switch p.Action {
+	case "labels":
+		var ids []string
+		if p.Params["ids"] != "" {
+			ids = strings.Split(p.Params["ids"], ",")
+		}
+		out := runLabels([]Account{account}, p.Params["action"], p.Params["label"], ids, false)
+		if len(out.Errors) > 0 {
+			return fmt.Errorf("%s", out.Errors[0].Error)
+		}
+		for _, a := range out.Assigned {
+			if a.Error != "" {
+				return fmt.Errorf("thread %s: %s", a.ThreadID, a.Error)
+			}
+		}
+		return nil
+	case "send":
+		return sendICSReply(account, p.Params["to"], p.Params["subject"], p.Params["ics"])
+	}
+	return fmt.Errorf("unknown pending action %q", p.Action)
+}
+
+// runApprove executes the pending action with the given ID, or all of them
+// for ALL. Actions that fail stay pending so they can be retried.
+func runApprove(target string) PendingOutput {
+	output := PendingOutput{Pending: []PendingAction{}}
+	pending := loadPending()
+	found := false
+	for _, p := range pending {
+		if target != "ALL" && p.ID != target {
+			output.Pending = append(output.Pending, p)
+			continue
+		}
+		found = true
+		result := ApprovalResult{PendingAction: p}
+		if err := executePending(p); err != nil {
+			result.Error = err.Error()
+			output.Pending = append(output.Pending, p)
+		} else {
+			result.Done = true
+		}
+		output.Approved = append(output.Approved, result)
+	}
+	if !found && target != "ALL" {
+		output.Errors = append(output.Errors, AccountError{Error: fmt.Sprintf("no pending action %s", target)})
+		return output
+	}
+	if err := saveState("pending.json", output.Pending); err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+	}
+	return output
+}
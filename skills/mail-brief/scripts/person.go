@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// --- Person View ---
+
+// --person narrows the brief to mail exchanged with one person and sums it
+// up by thread: who wrote last, which threads wait on my reply, and the
+// files either side shared. Without a date flag it looks back a month.
+
+const personLookback = "newer_than:30d"
+
+type PersonThread struct {
+	ThreadID    string `json:"thread_id"`
+	Subject     string `json:"subject"`
+	LastDate    string `json:"last_date"`
+	LastFrom    string `json:"last_from"` // them, me or other
+	Messages    int    `json:"messages"`  // in the range searched
+	Unread      bool   `json:"unread,omitempty"`
+	AccountType string `json:"account_type"`
+}
+
+type SharedFile struct {
+	Name     string `json:"name"`
+	URL      string `json:"url,omitempty"`
+	Subject  string `json:"subject"`
+	Date     string `json:"date"`
+	SharedBy string `json:"shared_by"` // them, me or other
+}
+
+type PersonThreads struct {
+	Email         string         `json:"email"`
+	Threads       []PersonThread `json:"threads"`        // most recent first
+	AwaitingReply []PersonThread `json:"awaiting_reply"` // they wrote last
+	Files         []SharedFile   `json:"files"`
+}
+
+var driveLinkPattern = regexp.MustCompile(`https://(?:docs|drive|sheets|slides)\.google\.com/[^\s"'<>)\]]+`)
+
+// personQuery matches mail from, to or copied to email.
+func personQuery(email string) string {
+	return fmt.Sprintf("(from:%s OR to:%s OR cc:%s)", email, email, email)
+}
+
+// sentBy says who sent msg: the person, one of my accounts, or someone
+// else on the thread.
+func sentBy(msg SimplifiedMessage, email string, accounts []Account) string {
+	if strings.EqualFold(msg.FromEmail, email) {
+		return "them"
+	}
+	for _, a := range accounts {
+		if strings.EqualFold(msg.FromEmail, a.Email) {
+			return "me"
+		}
+	}
+	return "other"
+}
+
+// sharedFiles lists the attachments and Google Drive links on one message.
+// It reads the body, so confidential messages are left to the caller.
+func sharedFiles(raw GogMessage, msg SimplifiedMessage, email string, accounts []Account) []SharedFile {
+	var files []SharedFile
+	add := func(name, url string) {
+		files = append(files, SharedFile{Name: name, URL: url, Subject: msg.Subject, Date: msg.Date, SharedBy: sentBy(msg, email, accounts)})
+	}
+	for _, name := range raw.Attachments {
+		add(name, "")
+	}
+	for _, url := range driveLinkPattern.FindAllString(raw.Snippet+"\n"+raw.Body, -1) {
+		add(url, url)
+	}
+	return files
+}
+
+// summarizePerson groups the messages into threads, newest first.
+func summarizePerson(messages []SimplifiedMessage, email string, accounts []Account, files []SharedFile) *PersonThreads {
+	view := &PersonThreads{Email: email, Threads: []PersonThread{}, AwaitingReply: []PersonThread{}, Files: files}
+	if view.Files == nil {
+		view.Files = []SharedFile{}
+	}
+
+	sorted := append([]SimplifiedMessage(nil), messages...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		ti, _ := parseMessageDate(sorted[i].Date)
+		tj, _ := parseMessageDate(sorted[j].Date)
+		return ti.After(tj)
+	})
+	index := map[string]int{}
+	for _, m := range sorted {
+		key := m.threadID
+		if key == "" {
+			key = m.id
+		}
+		if i, ok := index[key]; ok {
+			view.Threads[i].Messages++
+			view.Threads[i].Unread = view.Threads[i].Unread || m.IsUnread
+			continue
+		}
+		index[key] = len(view.Threads)
+		view.Threads = append(view.Threads, PersonThread{
+			ThreadID:    m.threadID,
+			Subject:     m.Subject,
+			LastDate:    m.Date,
+			LastFrom:    sentBy(m, email, accounts),
+			Messages:    1,
+			Unread:      m.IsUnread,
+			AccountType: m.AccountType,
+		})
+	}
+	for _, t := range view.Threads {
+		if t.LastFrom == "them" {
+			view.AwaitingReply = append(view.AwaitingReply, t)
+		}
+	}
+	return view
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// --- Spoofing & Phishing Heuristics ---
+
+var (
+	authorityNamePattern = regexp.MustCompile(`(?i)\b(ceo|cfo|coo|cto|president|director|payroll|finance|accounting|it support|helpdesk|security team|admin(istrator)?)\b|대표|사장|회장|이사|부장|인사팀|재무팀|보안팀|관리자`)
+	addressInNamePattern = regexp.MustCompile(`[\w.+-]+@([\w-]+(?:\.[\w-]+)+)`)
+	authFailPattern      = regexp.MustCompile(`(?i)\b(spf|dkim|dmarc)=(fail|softfail|permerror)\b`)
+)
+
+// impersonatedDomains are frequently spoofed senders, checked for lookalikes
+// alongside my own account domains.
+var impersonatedDomains = []string{
+	"google.com", "gmail.com", "microsoft.com", "outlook.com", "apple.com", "icloud.com",
+	"amazon.com", "paypal.com", "naver.com", "kakao.com", "kakaocorp.com", "daum.net",
+	"github.com", "slack.com", "zoom.us", "docusign.net", "dropbox.com",
+}
+
+func emailDomain(addr string) string {
+	if i := strings.LastIndex(addr, "@"); i >= 0 {
+		return strings.ToLower(strings.TrimSpace(addr[i+1:]))
+	}
+	return ""
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// lookalikeOf returns the trusted domain that domain imitates: punycode, or
+// one or two edits away from a trusted domain without being it.
+func lookalikeOf(domain string, trusted []string) (string, bool) {
+	for _, t := range trusted {
+		if domain == t || strings.HasSuffix(domain, "."+t) {
+			return "", false
+		}
+	}
+	for _, t := range trusted {
+		limit := 1
+		if len(t) >= 10 {
+			limit = 2
+		}
+		if d := editDistance(domain, t); d > 0 && d <= limit {
+			return t, true
+		}
+	}
+	if strings.HasPrefix(domain, "xn--") || strings.Contains(domain, ".xn--") {
+		return domain, true
+	}
+	return "", false
+}
+
+// suspiciousReasons lists why a message looks spoofed. myDomains are the
+// domains of my own accounts.
+func suspiciousReasons(raw GogMessage, msg SimplifiedMessage, myDomains []string) []string {
+	var reasons []string
+	domain := emailDomain(msg.FromEmail)
+	if domain == "" {
+		return nil
+	}
+	internal := false
+	for _, d := range myDomains {
+		if domain == d && !personalDomains[d] {
+			internal = true
+		}
+	}
+
+	if m := addressInNamePattern.FindStringSubmatch(msg.FromName); m != nil && !strings.EqualFold(m[1], domain) {
+		reasons = append(reasons, "display_name_address_mismatch")
+	} else if !internal && authorityNamePattern.MatchString(msg.FromName) {
+		reasons = append(reasons, "display_name_spoof")
+	}
+
+	trusted := append([]string{}, impersonatedDomains...)
+	for _, d := range myDomains {
+		if !personalDomains[d] {
+			trusted = append(trusted, d)
+		}
+	}
+	if _, ok := lookalikeOf(domain, trusted); ok {
+		reasons = append(reasons, "lookalike_domain")
+	}
+
+	auth := raw.AuthenticationResults
+	if auth == "" {
+		auth = headerValue(raw, "Authentication-Results")
+	}
+	if authFailPattern.MatchString(auth) {
+		reasons = append(reasons, "auth_failed")
+	}
+	return reasons
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/base64"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// --- Message Previews ---
+
+// --snippet adds a short preview of each message, so an interesting one can
+// be judged without fetching it again. Bare, it is Gmail's own snippet;
+// --snippet=N takes the first N characters of the decoded body instead,
+// which fetchBodies gets for each message, falling back to the snippet
+// when that fetch fails.
+
+// gmailSnippet is the --snippet value for Gmail's snippet as-is.
+const gmailSnippet = -1
+
+// snippetFlag makes --snippet work both bare and as --snippet=N.
+type snippetFlag int
+
+func (f *snippetFlag) String() string { return strconv.Itoa(int(*f)) }
+
+func (f *snippetFlag) Set(s string) error {
+	switch s {
+	case "true":
+		*f = gmailSnippet
+	case "false":
+		*f = 0
+	default:
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return strconv.ErrSyntax
+		}
+		*f = snippetFlag(n)
+	}
+	return nil
+}
+
+func (f *snippetFlag) IsBoolFlag() bool { return true }
+
+var (
+	htmlBreakPattern = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|li|tr|h\d)>`)
+	htmlTagPattern   = regexp.MustCompile(`(?s)<(style|script)[^>]*>.*?</(style|script)>|<[^>]*>`)
+)
+
+// plainText strips HTML, collapses whitespace, and cuts s to limit
+// characters (no limit when limit <= 0).
+func plainText(s string, limit int) string {
+	s = htmlBreakPattern.ReplaceAllString(s, " ")
+	s = html.UnescapeString(htmlTagPattern.ReplaceAllString(s, ""))
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); limit > 0 && len(r) > limit {
+		s = strings.TrimSpace(string(r[:limit])) + "…"
+	}
+	return s
+}
+
+// findTextPart returns the decoded body of the first part of mimeType.
+func findTextPart(part GogMessagePart, mimeType string) (string, bool) {
+	if strings.HasPrefix(strings.ToLower(part.MimeType), mimeType) {
+		data := part.Body.Data
+		decoded, err := base64.URLEncoding.DecodeString(data)
+		if err != nil {
+			decoded, err = base64.RawURLEncoding.DecodeString(data)
+		}
+		if err == nil && len(decoded) > 0 {
+			return string(decoded), true
+		}
+	}
+	for _, p := range part.Parts {
+		if text, ok := findTextPart(p, mimeType); ok {
+			return text, true
+		}
+	}
+	return "", false
+}
+
+// messageBody is the message text: gog's body when it printed one, else the
+// plain or HTML part of the payload.
+func messageBody(msg GogMessage) string {
+	if msg.Body != "" {
+		return msg.Body
+	}
+	root := GogMessagePart{MimeType: msg.MimeType, Parts: msg.Parts}
+	if msg.Payload != nil {
+		root = *msg.Payload
+	}
+	for _, mimeType := range []string{"text/plain", "text/html"} {
+		if text, ok := findTextPart(root, mimeType); ok {
+			return text
+		}
+	}
+	return ""
+}
+
+// messageText is the whole body of msg as plain text, or its snippet when
+// gog printed no body.
+func messageText(msg GogMessage) string {
+	if body := plainText(messageBody(msg), 0); body != "" {
+		return body
+	}
+	return plainText(msg.Snippet, 0)
+}
+
+// messagePreview is the --snippet text of msg.
+func messagePreview(msg GogMessage, limit snippetFlag) string {
+	if limit != gmailSnippet {
+		if body := plainText(messageBody(msg), int(limit)); body != "" {
+			return body
+		}
+	}
+	return plainText(msg.Snippet, int(limit))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"time"
+)
+
+// --- Scheduled & Future-dated Mail ---
+
+type ScheduledItem struct {
+	Kind        string `json:"kind"` // scheduled_send or future_meeting
+	Date        string `json:"date"`
+	Subject     string `json:"subject"`
+	FromEmail   string `json:"from_email"`
+	To          string `json:"to,omitempty"`
+	RefersTo    string `json:"refers_to,omitempty"`
+	AccountType string `json:"account_type"`
+}
+
+var meetingMailPattern = regexp.MustCompile(`(?i)\b(invitation|invite|meeting|call|sync|interview|reschedul\w*|updated)\b|초대|회의|미팅|면접|일정`)
+
+// fetchScheduled lists messages sitting in the account's Scheduled folder,
+// i.e. replies already written and queued to send later.
+func fetchScheduled(ctx context.Context, account Account) ([]ScheduledItem, error) {
+	rawMessages, err := fetchMessages(ctx, account.Email, "in:scheduled", 50)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]ScheduledItem, 0, len(rawMessages))
+	for _, raw := range rawMessages {
+		msg := simplifyMessage(raw, account.Type)
+		items = append(items, ScheduledItem{
+			Kind:        "scheduled_send",
+			Date:        msg.Date,
+			Subject:     msg.Subject,
+			FromEmail:   msg.FromEmail,
+			To:          raw.To,
+			AccountType: msg.AccountType,
+		})
+	}
+	return items, nil
+}
+
+// futureMeetingItem reports meeting-related mail whose subject mentions a date
+// after today, which usually means the scheduling is already settled.
+func futureMeetingItem(msg SimplifiedMessage, now time.Time) (ScheduledItem, bool) {
+	if !meetingMailPattern.MatchString(msg.Subject) {
+		return ScheduledItem{}, false
+	}
+	ref, ok := parseMessageDate(msg.Date)
+	if !ok {
+		ref = now
+	}
+	due, ok := parseDueDate(msg.Subject, ref)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if !ok || !due.After(today) {
+		return ScheduledItem{}, false
+	}
+	return ScheduledItem{
+		Kind:        "future_meeting",
+		Date:        msg.Date,
+		Subject:     msg.Subject,
+		FromEmail:   msg.FromEmail,
+		RefersTo:    due.Format("2006-01-02"),
+		AccountType: msg.AccountType,
+	}, true
+}
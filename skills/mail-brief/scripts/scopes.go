@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// --- Scopes & Read-only Mode ---
+
+type AccountScopes struct {
+	Email    string   `json:"email"`
+	Type     string   `json:"type"`
+	Services []string `json:"services,omitempty"`
+	Scopes   []string `json:"scopes,omitempty"`
+	Write    []string `json:"write"`     // what the granted scopes allow changing
+	ReadOnly bool     `json:"read_only"` // scopes are known and none allows writes
+}
+
+type ScopesOutput struct {
+	Accounts []AccountScopes `json:"accounts"`
+	Errors   []AccountError  `json:"errors,omitempty"`
+}
+
+// writeScopes maps OAuth scope suffixes to the writes they allow. Read-only
+// scopes (gmail.readonly, gmail.metadata, calendar.readonly) are absent.
+var writeScopes = map[string][]string{
+	"mail.google.com/":     {"gmail_send", "gmail_modify", "gmail_labels"},
+	"gmail.modify":         {"gmail_send", "gmail_modify", "gmail_labels"},
+	"gmail.send":           {"gmail_send"},
+	"gmail.compose":        {"gmail_send"},
+	"gmail.labels":         {"gmail_labels"},
+	"gmail.settings.basic": {"gmail_settings"},
+	"calendar":             {"calendar_write"},
+	"calendar.events":      {"calendar_write"},
+}
+
+// scopeWrites lists the writes the given scopes allow, deduplicated in
+// first-seen order.
+func scopeWrites(scopes []string) []string {
+	writes := []string{}
+	seen := map[string]bool{}
+	for _, scope := range scopes {
+		name := strings.TrimPrefix(scope, "https://www.googleapis.com/auth/")
+		name = strings.TrimPrefix(name, "https://")
+		for _, w := range writeScopes[name] {
+			if !seen[w] {
+				seen[w] = true
+				writes = append(writes, w)
+			}
+		}
+	}
+	return writes
+}
+
+// runScopes reports the services and scopes gog holds for each account.
+// gog only lists scopes for some token types; without them the write
+// capabilities are unknown and read_only stays false.
+func runScopes(accounts []Account) ScopesOutput {
+	output := ScopesOutput{Accounts: []AccountScopes{}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "gog", "auth", "list", "--json").Output()
+	var data struct {
+		Accounts []struct {
+			Email    string   `json:"email"`
+			Services []string `json:"services"`
+			Scopes   []string `json:"scopes"`
+		} `json:"accounts"`
+	}
+	if err == nil {
+		err = json.Unmarshal(out, &data)
+	}
+	if err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: fmt.Sprintf("gog auth list: %v", err)})
+		return output
+	}
+
+	byEmail := map[string]int{}
+	for i, a := range data.Accounts {
+		byEmail[strings.ToLower(a.Email)] = i
+	}
+	for _, account := range accounts {
+		i, ok := byEmail[strings.ToLower(account.Email)]
+		if !ok {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: "not authorized in gog"})
+			continue
+		}
+		s := AccountScopes{
+			Email:    account.Email,
+			Type:     account.Type,
+			Services: data.Accounts[i].Services,
+			Scopes:   data.Accounts[i].Scopes,
+		}
+		s.Write = scopeWrites(s.Scopes)
+		s.ReadOnly = len(s.Scopes) > 0 && len(s.Write) == 0
+		output.Accounts = append(output.Accounts, s)
+	}
+	return output
+}
+
+// readOnlyEnv turns on --read-only for every run, for users who never want
+// the tools to write.
+const readOnlyEnv = "BRIEF_READ_ONLY"
+
+func readOnlyFromEnv() bool {
+	v := strings.ToLower(os.Getenv(readOnlyEnv))
+	return v != "" && v != "0" && v != "false"
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// --- Local State ---
+
+// statePath returns the location of a state file under the user cache
+// directory (~/.cache/mail-brief on Linux, ~/Library/Caches/mail-brief on
+// macOS).
+func statePath(name string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "mail-brief", name), nil
+}
+
+// loadState decodes a state file into v. A missing or unreadable file leaves
+// v untouched and reports false.
+func loadState(name string, v interface{}) bool {
+	path, err := statePath(name)
+	if err != nil {
+		return false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
+
+func saveState(name string, v interface{}) error {
+	path, err := statePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// --- Sender History ---
+
+// markFirstContacts flags messages whose sender has never been seen before and
+// records every sender with the date first seen. The very first run only
+// seeds the store (with an empty date), otherwise every sender would be
+// reported as new. Reruns on the same day keep reporting that day's newcomers.
+func markFirstContacts(messages []SimplifiedMessage, now time.Time) {
+	seen := map[string]string{}
+	seeded := loadState("senders.json", &seen)
+
+	today := now.Format("2006-01-02")
+	for i := range messages {
+		addr := strings.ToLower(messages[i].FromEmail)
+		if addr == "" {
+			continue
+		}
+		first, known := seen[addr]
+		if !known {
+			seen[addr] = ""
+			if seeded {
+				seen[addr] = today
+			}
+		}
+		messages[i].FirstContact = seeded && (!known || first == today)
+	}
+	saveState("senders.json", seen)
+}
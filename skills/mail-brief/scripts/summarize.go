@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// --- Summarizer Hook ---
+
+// summarizeTimeout is how long the summarizer may take over one message.
+const summarizeTimeout = 60 * time.Second
+
+// summarizeMessage pipes a plain-text rendering of the message, headers and
+// body, to an external command (run through `sh -c`, so pipes and arguments
+// work) and returns its trimmed stdout. It waits for a gogPool slot first,
+// so --concurrency bounds the summarizers as it does gog.
+func summarizeMessage(command string, msg SimplifiedMessage) (string, error) {
+	var input strings.Builder
+	fmt.Fprintf(&input, "From: %s <%s>\n", msg.FromName, msg.FromEmail)
+	fmt.Fprintf(&input, "Date: %s\n", msg.Date)
+	fmt.Fprintf(&input, "Subject: %s\n", msg.Subject)
+	if msg.body != "" {
+		fmt.Fprintf(&input, "\n%s\n", msg.body)
+	}
+
+	release, err := gogPool.acquire(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), summarizeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = strings.NewReader(input.String())
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return "", fmt.Errorf("summarizer failed: %s", errMsg)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// summarizeMessages fills in Summary, or SummaryError, on every message that
+// may be shown, running the summarizer on several at once.
+func summarizeMessages(messages []SimplifiedMessage, command string, includeConfidential bool) {
+	parallel(len(messages), func(i int) {
+		if messages[i].Confidential && !includeConfidential {
+			return
+		}
+		summary, err := summarizeMessage(command, messages[i])
+		if err != nil {
+			messages[i].SummaryError = err.Error()
+			return
+		}
+		messages[i].Summary = summary
+	})
+}
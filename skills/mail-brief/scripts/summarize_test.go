@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// --- Summarizer Hook ---
+
+func TestSummarizeMessagesPipesBody(t *testing.T) {
+	messages := []SimplifiedMessage{
+		{Subject: "Budget", FromEmail: "cfo@corp.example", body: "Please approve the Q4 budget by Friday."},
+		{Subject: "Sealed", Confidential: true, body: "not for the summarizer"},
+	}
+	summarizeMessages(messages, "cat", false)
+	if got := messages[0].Summary; !strings.Contains(got, "Subject: Budget") || !strings.Contains(got, "approve the Q4 budget") {
+		t.Errorf("summarizer input = %q, want headers and body", got)
+	}
+	if messages[1].Summary != "" || messages[1].SummaryError != "" {
+		t.Errorf("confidential message was summarized: %+v", messages[1])
+	}
+
+	summarizeMessages(messages[:1], "echo broken >&2; exit 3", false)
+	if messages[0].SummaryError != "summarizer failed: broken" {
+		t.Errorf("SummaryError = %q", messages[0].SummaryError)
+	}
+}
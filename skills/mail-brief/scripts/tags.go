@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- Annotations ---
+
+// Tags and a note attached to a sender with --tag are kept in
+// annotations.json in the state directory and copied onto every later
+// message from that address, so a triage decision made one morning is still
+// visible the next day.
+
+type Annotation struct {
+	Tags    []string `json:"tags,omitempty"`
+	Note    string   `json:"note,omitempty"`
+	Updated string   `json:"updated"`
+}
+
+type AnnotationsOutput struct {
+	Annotations map[string]Annotation `json:"annotations"` // by sender email, lowercased
+	Errors      []AccountError        `json:"errors,omitempty"`
+}
+
+func loadAnnotations() map[string]Annotation {
+	annotations := map[string]Annotation{}
+	loadState("annotations.json", &annotations)
+	return annotations
+}
+
+// parseTagSpec splits "alice@corp.com:tag1,tag2" into the sender and its
+// tags. The tags may be omitted, e.g. to attach only a note or to drop
+// everything.
+func parseTagSpec(spec string) (string, []string, error) {
+	id, list, _ := strings.Cut(spec, ":")
+	if id = strings.ToLower(strings.TrimSpace(id)); !strings.Contains(id, "@") {
+		return "", nil, fmt.Errorf("invalid tag %q (want EMAIL:tag)", spec)
+	}
+	var tags []string
+	for _, t := range strings.Split(list, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return id, tags, nil
+}
+
+// runTag adds the tags in spec (and note, when set) to a sender.
+func runTag(spec, note string, now time.Time) AnnotationsOutput {
+	annotations := loadAnnotations()
+	output := AnnotationsOutput{Annotations: annotations}
+	id, tags, err := parseTagSpec(spec)
+	if err == nil && len(tags) == 0 && note == "" {
+		err = fmt.Errorf("--tag %s has neither tags nor --note", spec)
+	}
+	if err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+		return output
+	}
+	a := annotations[id]
+	for _, t := range tags {
+		if !containsString(a.Tags, t) {
+			a.Tags = append(a.Tags, t)
+		}
+	}
+	sort.Strings(a.Tags)
+	if note != "" {
+		a.Note = note
+	}
+	a.Updated = now.Format(time.RFC3339)
+	annotations[id] = a
+	if err := saveState("annotations.json", annotations); err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+	}
+	return output
+}
+
+// runUntag removes the tags in spec from a sender, or its whole annotation
+// (note included) when spec names no tags.
+func runUntag(spec string) AnnotationsOutput {
+	annotations := loadAnnotations()
+	output := AnnotationsOutput{Annotations: annotations}
+	id, tags, err := parseTagSpec(spec)
+	if err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+		return output
+	}
+	a, ok := annotations[id]
+	if !ok {
+		output.Errors = append(output.Errors, AccountError{Error: fmt.Sprintf("no annotation for %s", id)})
+		return output
+	}
+	if len(tags) == 0 {
+		delete(annotations, id)
+	} else {
+		var kept []string
+		for _, t := range a.Tags {
+			if !containsString(tags, t) {
+				kept = append(kept, t)
+			}
+		}
+		a.Tags = kept
+		if len(kept) == 0 && a.Note == "" {
+			delete(annotations, id)
+		} else {
+			annotations[id] = a
+		}
+	}
+	if err := saveState("annotations.json", annotations); err != nil {
+		output.Errors = append(output.Errors, AccountError{Error: err.Error()})
+	}
+	return output
+}
+
+// annotateSenders copies each sender's annotation onto their messages.
+func annotateSenders(messages []SimplifiedMessage) {
+	annotations := loadAnnotations()
+	if len(annotations) == 0 {
+		return
+	}
+	for i := range messages {
+		if a, ok := annotations[strings.ToLower(messages[i].FromEmail)]; ok {
+			messages[i].Tags, messages[i].Note = a.Tags, a.Note
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
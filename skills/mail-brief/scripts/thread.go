@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Thread Timeline ---
+
+type TimelineEntry struct {
+	Date         string   `json:"date"`
+	FromName     string   `json:"from_name"`
+	FromEmail    string   `json:"from_email"`
+	Subject      string   `json:"subject"`
+	Snippet      string   `json:"snippet,omitempty"`
+	Attachments  []string `json:"attachments"`
+	Decision     bool     `json:"decision"`
+	Confidential bool     `json:"confidential,omitempty"`
+
+	sortKey time.Time
+}
+
+type ThreadOutput struct {
+	ThreadID string          `json:"thread_id"`
+	Account  *Account        `json:"account,omitempty"`
+	Timeline []TimelineEntry `json:"timeline"`
+	Errors   []AccountError  `json:"errors,omitempty"`
+}
+
+var decisionPattern = regexp.MustCompile(`(?i)\b(decided|decision|agreed|approved|let'?s go with|final(?:ized)?|sign(?:ed)? off)\b|결정|합의|확정|승인`)
+
+func fetchThread(accountEmail, threadID string) (*GogThread, error) {
+	args := []string{"gmail", "thread", "get", threadID, "--json", fmt.Sprintf("--account=%s", accountEmail)}
+
+	out, err := runGog(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// gog may wrap the Gmail thread resource in a "thread" key.
+	var data struct {
+		GogThread
+		Thread *GogThread `json:"thread"`
+	}
+	if err := json.Unmarshal(out, &data); err != nil {
+		return nil, fmt.Errorf("unexpected JSON format from gog")
+	}
+	if data.Thread != nil {
+		return data.Thread, nil
+	}
+	return &data.GogThread, nil
+}
+
+// headerValue reads a header from a Gmail API payload, for thread messages
+// that come back in the raw API shape instead of gog's flattened one.
+func headerValue(msg GogMessage, name string) string {
+	if msg.Payload == nil {
+		return ""
+	}
+	for _, h := range msg.Payload.Headers {
+		if strings.EqualFold(h.Name, name) {
+			return h.Value
+		}
+	}
+	return ""
+}
+
+func collectAttachments(part GogMessagePart, names []string) []string {
+	if part.Filename != "" {
+		names = append(names, part.Filename)
+	}
+	for _, p := range part.Parts {
+		names = collectAttachments(p, names)
+	}
+	return names
+}
+
+// buildTimeline orders a thread's messages by date. Snippets of confidential
+// messages are dropped unless includeConfidential is set.
+func buildTimeline(thread *GogThread, includeConfidential bool) []TimelineEntry {
+	entries := make([]TimelineEntry, 0, len(thread.Messages))
+
+	for _, msg := range thread.Messages {
+		from := msg.From
+		if from == "" {
+			from = headerValue(msg, "From")
+		}
+		subject := msg.Subject
+		if subject == "" {
+			subject = headerValue(msg, "Subject")
+		}
+		date := msg.Date
+		if date == "" {
+			date = headerValue(msg, "Date")
+		}
+		snippet := msg.Snippet
+		confidential := isConfidential(msg)
+		if confidential && !includeConfidential {
+			snippet = ""
+		}
+
+		entry := TimelineEntry{
+			Date:         date,
+			Subject:      subject,
+			Snippet:      snippet,
+			Attachments:  []string{},
+			Decision:     decisionPattern.MatchString(subject) || decisionPattern.MatchString(snippet),
+			Confidential: confidential,
+		}
+		entry.FromName, entry.FromEmail = parseFrom(from)
+
+		if msg.Attachments != nil {
+			entry.Attachments = msg.Attachments
+		} else if msg.Payload != nil {
+			entry.Attachments = collectAttachments(*msg.Payload, entry.Attachments)
+		}
+
+		if t, ok := parseMessageDate(date); ok {
+			entry.sortKey = t
+		} else if ms, err := strconv.ParseInt(msg.InternalDate, 10, 64); err == nil {
+			entry.sortKey = time.UnixMilli(ms)
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].sortKey.Before(entries[j].sortKey)
+	})
+	return entries
+}
+
+// runThread looks the thread up in each account in turn; thread IDs are only
+// valid in the mailbox they came from.
+func runThread(accounts []Account, threadID string, includeConfidential bool) ThreadOutput {
+	output := ThreadOutput{ThreadID: threadID, Timeline: []TimelineEntry{}}
+	for i, account := range accounts {
+		thread, err := fetchThread(account.Email, threadID)
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			continue
+		}
+		output.Account = &accounts[i]
+		output.Timeline = buildTimeline(thread, includeConfidential)
+		output.Errors = nil
+		break
+	}
+	return output
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+)
+
+// --- Undo ---
+
+type UndoOutput struct {
+	Entry  *AuditEntry    `json:"entry,omitempty"` // the action that was reversed
+	Undone bool           `json:"undone"`
+	Errors []AccountError `json:"errors,omitempty"`
+}
+
+// undoArgs returns the gog command that reverses a logged action. Sent mail
+// cannot be recalled.
+func undoArgs(e AuditEntry) ([]string, error) {
+	account := fmt.Sprintf("--account=%s", e.Account)
+	switch e.Action {
+	case "label_add":
+		return []string{"gmail", "thread", "modify", e.Params["thread_id"], fmt.Sprintf("--remove=%s", e.Params["label_id"]), account}, nil
+	case "label_create":
+		return []string{"gmail", "labels", "delete", e.Params["label_id"], account}, nil
+	case "send":
+		return nil, fmt.Errorf("sent mail cannot be undone")
+	}
+	return nil, fmt.Errorf("%s cannot be undone", e.Action)
+}
+
+// findUndoTarget picks the entry to reverse: target is an entry ID, or LAST
+// for the newest successful action that has not been undone yet.
+func findUndoTarget(entries []AuditEntry, target string) (AuditEntry, error) {
+	undone := map[string]bool{}
+	for _, e := range entries {
+		if e.Action == "undo" && e.Error == "" {
+			undone[e.Params["entry"]] = true
+		}
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if target == "LAST" && (e.Action == "undo" || e.Action == "send" || e.Error != "" || undone[e.ID]) {
+			continue
+		}
+		if target != "LAST" && e.ID != target {
+			continue
+		}
+		switch {
+		case e.Action == "undo":
+			return e, fmt.Errorf("entry %s is itself an undo", e.ID)
+		case e.Error != "":
+			return e, fmt.Errorf("entry %s failed, so there is nothing to undo", e.ID)
+		case undone[e.ID]:
+			return e, fmt.Errorf("entry %s has already been undone", e.ID)
+		}
+		return e, nil
+	}
+	if target == "LAST" {
+		return AuditEntry{}, fmt.Errorf("no action left to undo")
+	}
+	return AuditEntry{}, fmt.Errorf("no audit entry %s", target)
+}
+
+// runUndo reverses one logged action and records the undo itself.
+func runUndo(target string) UndoOutput {
+	var output UndoOutput
+	fail := func(email string, err error) UndoOutput {
+		output.Errors = append(output.Errors, AccountError{Email: email, Error: err.Error()})
+		return output
+	}
+	entries, err := readAudit()
+	if err != nil {
+		return fail("", err)
+	}
+	entry, err := findUndoTarget(entries, target)
+	if entry.ID != "" {
+		output.Entry = &entry
+	}
+	if err != nil {
+		return fail(entry.Account, err)
+	}
+	args, err := undoArgs(entry)
+	if err != nil {
+		return fail(entry.Account, err)
+	}
+	_, err = runGog(args...)
+	recordAction("undo", entry.Account, map[string]string{"entry": entry.ID, "action": entry.Action}, err)
+	if err != nil {
+		return fail(entry.Account, err)
+	}
+	output.Undone = true
+	return output
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// --- Storage Usage ---
+
+// storageWarnPercent is the usage level at which an account is flagged, well
+// before Gmail starts bouncing incoming mail at 100%.
+const storageWarnPercent = 90.0
+
+type AccountUsage struct {
+	Email       string  `json:"email"`
+	Type        string  `json:"type"`
+	LimitBytes  int64   `json:"limit_bytes,omitempty"`
+	UsageBytes  int64   `json:"usage_bytes"`
+	DriveBytes  int64   `json:"drive_bytes"`
+	TrashBytes  int64   `json:"trash_bytes"`
+	PercentUsed float64 `json:"percent_used,omitempty"`
+	NearlyFull  bool    `json:"nearly_full"`
+	Unlimited   bool    `json:"unlimited,omitempty"`
+}
+
+type UsageOutput struct {
+	Usage  []AccountUsage `json:"usage"`
+	Errors []AccountError `json:"errors,omitempty"`
+}
+
+func fetchUsage(account Account) (AccountUsage, error) {
+	args := []string{"drive", "about", "--json", fmt.Sprintf("--account=%s", account.Email)}
+
+	out, err := runGog(args...)
+	if err != nil {
+		return AccountUsage{}, err
+	}
+
+	// Drive's About resource. The quota is shared by Gmail, Drive, and
+	// Photos; byte counts are int64 values encoded as strings and limit is
+	// absent for unlimited plans.
+	var about struct {
+		StorageQuota struct {
+			Limit             string `json:"limit"`
+			Usage             string `json:"usage"`
+			UsageInDrive      string `json:"usageInDrive"`
+			UsageInDriveTrash string `json:"usageInDriveTrash"`
+		} `json:"storageQuota"`
+	}
+	if err := json.Unmarshal(out, &about); err != nil {
+		return AccountUsage{}, fmt.Errorf("unexpected JSON format from gog")
+	}
+
+	parse := func(s string) int64 {
+		n, _ := strconv.ParseInt(s, 10, 64)
+		return n
+	}
+	q := about.StorageQuota
+	usage := AccountUsage{
+		Email:      account.Email,
+		Type:       account.Type,
+		LimitBytes: parse(q.Limit),
+		UsageBytes: parse(q.Usage),
+		DriveBytes: parse(q.UsageInDrive),
+		TrashBytes: parse(q.UsageInDriveTrash),
+	}
+	if usage.LimitBytes > 0 {
+		usage.PercentUsed = math.Round(float64(usage.UsageBytes)/float64(usage.LimitBytes)*1000) / 10
+		usage.NearlyFull = usage.PercentUsed >= storageWarnPercent
+	} else {
+		usage.Unlimited = true
+	}
+	return usage, nil
+}
+
+func runUsage(accounts []Account) UsageOutput {
+	output := UsageOutput{Usage: []AccountUsage{}}
+	fetched := make([]AccountUsage, len(accounts))
+	failed := make([]error, len(accounts))
+	parallel(len(accounts), func(i int) {
+		fetched[i], failed[i] = fetchUsage(accounts[i])
+	})
+	for i, account := range accounts {
+		usage, err := fetched[i], failed[i]
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			continue
+		}
+		output.Usage = append(output.Usage, usage)
+	}
+	return output
+}
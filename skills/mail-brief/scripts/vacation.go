@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// --- Vacation Responder ---
+
+type VacationStatus struct {
+	Email   string `json:"email"`
+	Type    string `json:"type"`
+	Enabled bool   `json:"enabled"`
+	Active  bool   `json:"active"`
+	Start   string `json:"start,omitempty"`
+	End     string `json:"end,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+type VacationOutput struct {
+	Vacation []VacationStatus `json:"vacation"`
+	Errors   []AccountError   `json:"errors,omitempty"`
+}
+
+func fetchVacation(account Account, now time.Time) (VacationStatus, error) {
+	args := []string{"gmail", "vacation", "get", "--json", fmt.Sprintf("--account=%s", account.Email)}
+
+	out, err := runGog(args...)
+	if err != nil {
+		return VacationStatus{}, err
+	}
+
+	// Gmail's VacationSettings resource; startTime/endTime are epoch
+	// milliseconds encoded as strings.
+	var settings struct {
+		EnableAutoReply bool   `json:"enableAutoReply"`
+		ResponseSubject string `json:"responseSubject"`
+		StartTime       string `json:"startTime"`
+		EndTime         string `json:"endTime"`
+	}
+	if err := json.Unmarshal(out, &settings); err != nil {
+		return VacationStatus{}, fmt.Errorf("unexpected JSON format from gog")
+	}
+
+	status := VacationStatus{
+		Email:   account.Email,
+		Type:    account.Type,
+		Enabled: settings.EnableAutoReply,
+		Subject: settings.ResponseSubject,
+	}
+	start, hasStart := parseEpochMillis(settings.StartTime)
+	end, hasEnd := parseEpochMillis(settings.EndTime)
+	if hasStart {
+		status.Start = start.Local().Format(time.RFC3339)
+	}
+	if hasEnd {
+		status.End = end.Local().Format(time.RFC3339)
+	}
+	status.Active = status.Enabled &&
+		(!hasStart || !now.Before(start)) &&
+		(!hasEnd || now.Before(end))
+	return status, nil
+}
+
+func parseEpochMillis(s string) (time.Time, bool) {
+	if s == "" {
+		return time.Time{}, false
+	}
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(ms), true
+}
+
+func runVacation(accounts []Account) VacationOutput {
+	output := VacationOutput{Vacation: []VacationStatus{}}
+	now := time.Now()
+	fetched := make([]VacationStatus, len(accounts))
+	failed := make([]error, len(accounts))
+	parallel(len(accounts), func(i int) {
+		fetched[i], failed[i] = fetchVacation(accounts[i], now)
+	})
+	for i, account := range accounts {
+		status, err := fetched[i], failed[i]
+		if err != nil {
+			output.Errors = append(output.Errors, AccountError{Email: account.Email, Error: err.Error()})
+			continue
+		}
+		output.Vacation = append(output.Vacation, status)
+	}
+	return output
+}